@@ -0,0 +1,248 @@
+package main
+
+import (
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/anschnapp/pomodorofactory/pkg/keymap"
+	"github.com/anschnapp/pomodorofactory/pkg/plan"
+	"github.com/anschnapp/pomodorofactory/pkg/product"
+)
+
+// colonCommand is a parsed ":" command line.
+type colonCommand struct {
+	action   keymap.Action // run like the bound key would; "" for :select
+	product  int           // product to select first, -1 keeps the selection
+	duration time.Duration // work duration for :start, minutes to add for :extend; 0 = default
+	planEdit func(*plan.Plan) error
+	edit     string // product to open in the art editor
+}
+
+type commandSpec struct {
+	name   string
+	usage  string
+	states []appState // where the command makes sense
+}
+
+var colonCommands = []commandSpec{
+	{"start", "start [product] [minutes]", []appState{stateIdle}},
+	{"flow", "flow [product]", []appState{stateIdle}},
+	{"break", "break [short|long]", []appState{stateIdle, stateOnBreak}},
+	{"select", "select <product>", []appState{stateIdle}},
+	{"abort", "abort", []appState{stateWorking}},
+	{"extend", "extend <minutes>", []appState{stateWorking, stateOnBreak}},
+	{"skip", "skip", []appState{stateOnBreak}},
+	{"plan", "plan [add|rm|mv|clear ...]", nil},
+	{"warehouse", "warehouse", nil},
+	{"edit", "edit <product name>", []appState{stateIdle}},
+	{"help", "help", nil},
+	{"quit", "quit", nil},
+}
+
+func (c commandSpec) availableIn(state appState) bool {
+	if c.states == nil {
+		return true
+	}
+	for _, s := range c.states {
+		if s == state {
+			return true
+		}
+	}
+	return false
+}
+
+// lookupCommand accepts a command name or any unambiguous prefix (":q").
+func lookupCommand(name string) (commandSpec, error) {
+	var found []commandSpec
+	for _, c := range colonCommands {
+		if c.name == name {
+			return c, nil
+		}
+		if strings.HasPrefix(c.name, name) {
+			found = append(found, c)
+		}
+	}
+	switch len(found) {
+	case 0:
+		return commandSpec{}, fmt.Errorf("unknown command %q", name)
+	case 1:
+		return found[0], nil
+	}
+	return commandSpec{}, fmt.Errorf("%q is ambiguous", name)
+}
+
+// productSlug is how a product is typed on the command line: "coffee-cup".
+func productSlug(p *product.Product) string {
+	return strings.ToLower(strings.ReplaceAll(p.Name, " ", "-"))
+}
+
+func productSlugs(products []*product.Product) []string {
+	slugs := make([]string, len(products))
+	for i, p := range products {
+		slugs[i] = productSlug(p)
+	}
+	return slugs
+}
+
+// lookupProduct matches a slug, or an unambiguous prefix of one.
+func lookupProduct(products []*product.Product, name string) (int, error) {
+	name = strings.ToLower(name)
+	match := -1
+	for i, p := range products {
+		slug := productSlug(p)
+		if slug == name {
+			return i, nil
+		}
+		if strings.HasPrefix(slug, name) {
+			if match >= 0 {
+				return -1, fmt.Errorf("product %q is ambiguous", name)
+			}
+			match = i
+		}
+	}
+	if match < 0 {
+		return -1, fmt.Errorf("unknown product %q", name)
+	}
+	return match, nil
+}
+
+// parseCommand parses a command line (without the ":") and checks it
+// makes sense in the current state.
+func parseCommand(line string, products []*product.Product, state appState) (colonCommand, error) {
+	cmd := colonCommand{product: -1}
+	fields := strings.Fields(line)
+	if len(fields) == 0 {
+		return cmd, fmt.Errorf("empty command")
+	}
+	spec, err := lookupCommand(fields[0])
+	if err != nil {
+		return cmd, err
+	}
+	args := fields[1:]
+	usage := func() error { return fmt.Errorf("usage: :%s", spec.usage) }
+
+	if !spec.availableIn(state) {
+		return cmd, fmt.Errorf(":%s is not available right now", spec.name)
+	}
+
+	switch spec.name {
+	case "start":
+		cmd.action = keymap.ActionStart
+		if len(args) > 2 {
+			return cmd, usage()
+		}
+		for _, arg := range args {
+			if minutes, err := strconv.ParseFloat(arg, 64); err == nil {
+				if !validMinutes(minutes) {
+					return cmd, fmt.Errorf("duration must be %g to %d minutes", minMinutes, maxMinutes)
+				}
+				cmd.duration = time.Duration(minutes * float64(time.Minute))
+				continue
+			}
+			if cmd.product >= 0 {
+				return cmd, usage()
+			}
+			if cmd.product, err = lookupProduct(products, arg); err != nil {
+				return cmd, err
+			}
+		}
+	case "flow":
+		cmd.action = keymap.ActionFlow
+		if len(args) > 1 {
+			return cmd, usage()
+		}
+		if len(args) == 1 {
+			if cmd.product, err = lookupProduct(products, args[0]); err != nil {
+				return cmd, err
+			}
+		}
+	case "break":
+		cmd.action = keymap.ActionShortBreak
+		switch {
+		case len(args) == 0 || len(args) == 1 && args[0] == "short":
+		case len(args) == 1 && args[0] == "long":
+			cmd.action = keymap.ActionLongBreak
+		default:
+			return cmd, usage()
+		}
+	case "select":
+		if len(args) != 1 {
+			return cmd, usage()
+		}
+		if cmd.product, err = lookupProduct(products, args[0]); err != nil {
+			return cmd, err
+		}
+	case "abort":
+		cmd.action = keymap.ActionAbort
+	case "extend":
+		cmd.action = keymap.ActionExtend
+		if len(args) != 1 {
+			return cmd, usage()
+		}
+		minutes, err := strconv.ParseFloat(args[0], 64)
+		if err != nil || minutes == 0 || !(math.Abs(minutes) <= maxMinutes) {
+			return cmd, fmt.Errorf("extend: expected minutes up to %d, e.g. 5 or -5", maxMinutes)
+		}
+		cmd.duration = time.Duration(minutes * float64(time.Minute))
+	case "skip":
+		cmd.action = keymap.ActionSkipBreak
+	case "plan":
+		cmd.action = keymap.ActionPlan
+		if len(args) > 0 {
+			if cmd.planEdit, err = parsePlanEdit(args, products); err != nil {
+				return cmd, err
+			}
+		}
+	case "warehouse":
+		cmd.action = keymap.ActionWarehouse
+	case "edit":
+		if len(args) == 0 {
+			return cmd, usage()
+		}
+		cmd.edit = strings.Join(args, " ")
+	case "help":
+		cmd.action = keymap.ActionHelp
+	case "quit":
+		cmd.action = keymap.ActionQuit
+	}
+	return cmd, nil
+}
+
+// completeCommand offers command names, then product names or break kinds.
+func completeCommand(products []*product.Product) func(words []string, partial string) []string {
+	return func(words []string, partial string) []string {
+		if len(words) == 0 {
+			names := make([]string, len(colonCommands))
+			for i, c := range colonCommands {
+				names[i] = c.name
+			}
+			return names
+		}
+		spec, err := lookupCommand(words[0])
+		if err != nil {
+			return nil
+		}
+		if spec.name == "plan" {
+			switch {
+			case len(words) == 1:
+				return []string{"add", "rm", "mv", "clear"}
+			case len(words) == 2 && words[1] == "add":
+				return productSlugs(products)
+			}
+			return nil
+		}
+		if len(words) > 1 {
+			return nil
+		}
+		switch spec.name {
+		case "start", "flow", "select":
+			return productSlugs(products)
+		case "break":
+			return []string{"short", "long"}
+		}
+		return nil
+	}
+}
@@ -0,0 +1,85 @@
+package main
+
+import (
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+
+	"github.com/anschnapp/pomodorofactory/pkg/helpoverlay"
+	"github.com/anschnapp/pomodorofactory/pkg/keymap"
+	"github.com/anschnapp/pomodorofactory/pkg/schedule"
+)
+
+var stateTitles = map[appState]string{
+	stateIdle:                  "factory idle",
+	stateWorking:               "pomodoro running",
+	stateWaitingForCelebration: "pomodoro done",
+	stateCelebrating:           "celebrating",
+	stateOnBreak:               "cooldown",
+}
+
+// helpContent describes what can be done right now: the bound keys and
+// commands for the state, the schedule and where we are in it. completed
+// counts the pomodoros finished so far.
+func helpContent(km *keymap.Keymap, state appState, sched *schedule.Schedule, step int, flowRatio float64, completed int) (string, []helpoverlay.Section) {
+	ctx := state.keyContext()
+	var keys []helpoverlay.Row
+	for _, a := range keymap.Actions {
+		bound := km.Keys(ctx, a)
+		if len(bound) == 0 {
+			continue
+		}
+		labels := make([]string, len(bound))
+		for i, k := range bound {
+			labels[i] = keymap.DisplayKey(k)
+		}
+		keys = append(keys, helpoverlay.Row{Key: strings.Join(labels, " "), Description: keymap.Description(a)})
+	}
+
+	var commands []helpoverlay.Row
+	for _, c := range colonCommands {
+		if c.availableIn(state) {
+			commands = append(commands, helpoverlay.Row{Key: ":" + c.usage})
+		}
+	}
+
+	// One "work/break" pair of minutes per block with the current step in
+	// brackets, e.g. "25/5 [25]/5 25/5 25/15"
+	var blocks []string
+	for i := 0; i < len(sched.Steps); i += 2 {
+		pair := make([]string, 2)
+		for j := range pair {
+			pair[j] = strconv.FormatFloat(sched.Steps[i+j].Duration.Minutes(), 'f', -1, 64)
+			if i+j == step {
+				pair[j] = "[" + pair[j] + "]"
+			}
+		}
+		blocks = append(blocks, strings.Join(pair, "/"))
+	}
+	n, total := sched.Position(step)
+	durations := []helpoverlay.Row{
+		{Key: "schedule", Description: sched.Name},
+		{Key: "cycle", Description: strings.Join(blocks, " ")},
+	}
+	durations = append(durations,
+		helpoverlay.Row{Key: "position", Description: fmt.Sprintf("block %d of %d, %d done", n, total, completed)},
+		helpoverlay.Row{Key: "flow break", Description: fmt.Sprintf("%s of focus", formatRatio(flowRatio))},
+	)
+
+	sections := []helpoverlay.Section{
+		{Title: "Keys", Rows: keys},
+		{Title: "Commands", Rows: commands},
+		{Title: "Timer", Rows: durations},
+	}
+	return "Help: " + stateTitles[state], sections
+}
+
+// formatRatio shows 0.2 as "1/5" and anything that isn't one over a whole
+// number as a decimal.
+func formatRatio(r float64) string {
+	if inv := 1 / r; inv == math.Trunc(inv) {
+		return fmt.Sprintf("1/%d", int(inv))
+	}
+	return strconv.FormatFloat(r, 'g', 3, 64)
+}
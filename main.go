@@ -1,22 +1,36 @@
 package main
 
 import (
+	"flag"
 	"fmt"
+	"math"
 	"math/rand"
 	"os"
+	"slices"
 	"strconv"
 	"strings"
 	"time"
 
+	"github.com/anschnapp/pomodorofactory/pkg/arteditor"
 	"github.com/anschnapp/pomodorofactory/pkg/audio"
 	"github.com/anschnapp/pomodorofactory/pkg/celebration"
 	"github.com/anschnapp/pomodorofactory/pkg/commandinput"
+	"github.com/anschnapp/pomodorofactory/pkg/configdir"
 	"github.com/anschnapp/pomodorofactory/pkg/factoryscene"
+	"github.com/anschnapp/pomodorofactory/pkg/helpoverlay"
+	"github.com/anschnapp/pomodorofactory/pkg/input"
+	"github.com/anschnapp/pomodorofactory/pkg/keymap"
+	"github.com/anschnapp/pomodorofactory/pkg/megabuild"
 	"github.com/anschnapp/pomodorofactory/pkg/motivationcloud"
+	"github.com/anschnapp/pomodorofactory/pkg/plan"
 	"github.com/anschnapp/pomodorofactory/pkg/product"
+	"github.com/anschnapp/pomodorofactory/pkg/progression"
+	"github.com/anschnapp/pomodorofactory/pkg/render"
+	"github.com/anschnapp/pomodorofactory/pkg/schedule"
 	"github.com/anschnapp/pomodorofactory/pkg/status"
 	"github.com/anschnapp/pomodorofactory/pkg/timer"
 	"github.com/anschnapp/pomodorofactory/pkg/view"
+	"github.com/anschnapp/pomodorofactory/pkg/warehouse"
 	"golang.org/x/term"
 )
 
@@ -51,18 +65,68 @@ var (
 	}
 )
 
-func randomCongrats(productName string) string {
+// randomCongrats makes the celebration message, in the product's own words
+// where it has them.
+func randomCongrats(rng *rand.Rand, productName string, celebration product.Celebration) string {
+	cheers, verbs := congratsWords, verbWords
+	if len(celebration.Cheers) > 0 {
+		cheers = celebration.Cheers
+	}
+	if len(celebration.Verbs) > 0 {
+		verbs = celebration.Verbs
+	}
 	return fmt.Sprintf("%s we %s %s a %s %s",
-		congratsWords[rand.Intn(len(congratsWords))],
-		adverbWords[rand.Intn(len(adverbWords))],
-		verbWords[rand.Intn(len(verbWords))],
-		adjectiveWords[rand.Intn(len(adjectiveWords))],
+		cheers[rng.Intn(len(cheers))],
+		adverbWords[rng.Intn(len(adverbWords))],
+		verbs[rng.Intn(len(verbs))],
+		adjectiveWords[rng.Intn(len(adjectiveWords))],
 		strings.ToLower(productName),
 	)
 }
 
-func selectorLine(products []*product.Product, idx int) string {
-	return fmt.Sprintf("build next:  \u2190 [%s] \u2192", products[idx].Name)
+// selectorItems renders "build next:  ← [Name] →  3/8 planned" with
+// clickable arrows; clicking the name starts building it, clicking the plan
+// progress shows the plan.
+func selectorItems(km *keymap.Keymap, products []*product.Product, idx int, dayPlan *plan.Plan, counters *progression.Counters) []commandinput.Item {
+	items := []commandinput.Item{
+		{Text: "build next:  "},
+		{Text: km.KeyLabel(keymap.ContextIdle, keymap.ActionPrevProduct), Action: string(keymap.ActionPrevProduct)},
+		{Text: " "},
+		{Text: "[" + products[idx].Name + "]", Action: string(keymap.ActionStart)},
+		{Text: " "},
+		{Text: km.KeyLabel(keymap.ContextIdle, keymap.ActionNextProduct), Action: string(keymap.ActionNextProduct)},
+	}
+	if m, locked := counters.LockedBy(products[idx].Name); locked {
+		items = append(items,
+			commandinput.Item{Text: "  "},
+			commandinput.Item{Text: fmt.Sprintf("locked %d/%d", m.Progress(counters), m.Goal)},
+		)
+	} else if done, total := dayPlan.Progress(); total > 0 {
+		items = append(items,
+			commandinput.Item{Text: "  "},
+			commandinput.Item{Text: fmt.Sprintf("%d/%d planned", done, total), Action: string(keymap.ActionPlan)},
+		)
+	}
+	return items
+}
+
+// plannedProduct returns the index of the next product in the plan, or
+// current when nothing is left to build.
+func plannedProduct(dayPlan *plan.Plan, products []*product.Product, current int) int {
+	name, ok := dayPlan.Next()
+	if !ok {
+		return current
+	}
+	for i, p := range products {
+		if p.Name == name {
+			return i
+		}
+	}
+	return current
+}
+
+func today() string {
+	return time.Now().Format(plan.DateLayout)
 }
 
 type appState int
@@ -75,35 +139,334 @@ const (
 	stateOnBreak                               // break timer running (auto-started)
 )
 
+// keyContext returns the keymap context whose bindings apply in this state.
+func (s appState) keyContext() keymap.Context {
+	switch s {
+	case stateWorking:
+		return keymap.ContextWorking
+	case stateWaitingForCelebration:
+		return keymap.ContextWaiting
+	case stateCelebrating:
+		return keymap.ContextCelebrating
+	case stateOnBreak:
+		return keymap.ContextBreak
+	default:
+		return keymap.ContextIdle
+	}
+}
+
 const (
-	shortBreak      = 5 * time.Minute
-	longBreak       = 15 * time.Minute
-	pomodorosPerSet = 4
+	timerStep          = time.Minute      // what +/- add to or take off a running timer
+	autoStartCountdown = 10 * time.Second // grace period before --auto-start begins the next pomodoro
 )
 
-// Sentinel rune values for arrow keys (not valid Unicode)
+// Command bar and status hints are generated from the keymap so they
+// always show the keys that are actually bound.
+
+// droppableActions can leave a crowded command bar first, in this order;
+// the help overlay still lists them.
+var droppableActions = []keymap.Action{keymap.ActionCommand, keymap.ActionPlan, keymap.ActionFlow}
+
+// commandItems labels each bound action for the command bar; unbound ones
+// are left out. When the labels don't fit they are shortened, then the
+// droppable actions are left out, so no binding is ever cut off halfway.
+func commandItems(km *keymap.Keymap, ctx keymap.Context, actions ...keymap.Action) []commandinput.Item {
+	items := labelItems(actions, func(a keymap.Action) string { return km.Label(ctx, a) })
+	if rowWidth(items) <= commandinput.BarWidth {
+		return items
+	}
+	actions = slices.Clone(actions)
+	for _, drop := range append([]keymap.Action{""}, droppableActions...) {
+		actions = slices.DeleteFunc(actions, func(a keymap.Action) bool { return a == drop })
+		items = labelItems(actions, func(a keymap.Action) string { return km.ShortLabel(ctx, a) })
+		if rowWidth(items) <= commandinput.BarWidth {
+			break
+		}
+	}
+	return items
+}
+
+func labelItems(actions []keymap.Action, label func(keymap.Action) string) []commandinput.Item {
+	var items []commandinput.Item
+	for _, a := range actions {
+		if text := label(a); text != "" {
+			items = append(items, commandinput.Item{Text: text, Action: string(a)})
+		}
+	}
+	return items
+}
+
+// rowWidth is how many columns items take on the command row.
+func rowWidth(items []commandinput.Item) int {
+	width := 0
+	for i, item := range items {
+		if i > 0 {
+			width += len([]rune(commandinput.Separator))
+		}
+		width += len([]rune(item.Text))
+	}
+	return width
+}
+
+// idleCmdItems is the idle command bar; countingDown offers to cancel a
+// pending auto-start instead of the less urgent actions.
+func idleCmdItems(km *keymap.Keymap, countingDown bool) []commandinput.Item {
+	if countingDown {
+		return commandItems(km, keymap.ContextIdle, keymap.ActionStart, keymap.ActionCancel, keymap.ActionQuit)
+	}
+	return commandItems(km, keymap.ContextIdle, keymap.ActionStart, keymap.ActionFlow, keymap.ActionPlan, keymap.ActionCommand, keymap.ActionQuit)
+}
+
+func workCmdItems(km *keymap.Keymap, flowing bool) []commandinput.Item {
+	if flowing {
+		return commandItems(km, keymap.ContextWorking, keymap.ActionFinish, keymap.ActionAbort, keymap.ActionQuit)
+	}
+	return commandItems(km, keymap.ContextWorking, keymap.ActionAbort, keymap.ActionQuit)
+}
+
+// celebrationTitle is the banner shown during the party: a finished
+// mega-build is announced by name, a welded section by its number.
+func celebrationTitle(p *product.Product, progress *megabuild.Progress, grand bool) string {
+	switch {
+	case grand:
+		return strings.ToUpper(p.Name) + " COMPLETE!"
+	case p.IsMega():
+		return fmt.Sprintf("SECTION %d/%d COMPLETE!", progress.Done(p.Name, p.Sessions)+1, p.Sessions)
+	}
+	return "POMODORO COMPLETE!"
+}
+
+func idleStatusText(km *keymap.Keymap) string {
+	return fmt.Sprintf("Factory ready  press [%s] to start", km.KeyLabel(keymap.ContextIdle, keymap.ActionStart))
+}
+
+func breakCmdItems(km *keymap.Keymap, isLong bool) []commandinput.Item {
+	if isLong {
+		return commandItems(km, keymap.ContextBreak, keymap.ActionShortBreak, keymap.ActionSkipBreak, keymap.ActionQuit)
+	}
+	return commandItems(km, keymap.ContextBreak, keymap.ActionLongBreak, keymap.ActionSkipBreak, keymap.ActionQuit)
+}
+
+// loadKeymap resolves the --keymap flag: a preset name, a file path, or
+// (when empty) the user's keymap.conf falling back to the default preset.
+func loadKeymap(spec string) (*keymap.Keymap, error) {
+	if km, ok := keymap.Preset(spec); ok {
+		return km, nil
+	}
+	if spec != "" {
+		if _, err := os.Stat(spec); err != nil {
+			return nil, fmt.Errorf("keymap %q is neither a preset (%s) nor a readable file", spec, strings.Join(keymap.PresetNames, ", "))
+		}
+		return keymap.Load(spec)
+	}
+	path, err := configdir.Path("keymap.conf")
+	if err != nil {
+		return keymap.Default(), nil
+	}
+	return keymap.Load(path)
+}
+
+// Durations given in minutes run from about a second up to a day.
 const (
-	keyLeft  = rune(-1)
-	keyRight = rune(-2)
+	minMinutes = 0.02
+	maxMinutes = 24 * 60
 )
 
-func breakCmdText(isLong bool) string {
-	if isLong {
-		return "[s]mall cooldown | [q]uit"
+// validMinutes reports whether minutes is a usable timer length; NaN and
+// infinities are not.
+func validMinutes(minutes float64) bool {
+	return minutes >= minMinutes && minutes <= maxMinutes
+}
+
+// chooseSeed returns the --seed given to fs, or a time-based seed when
+// there was none.
+func chooseSeed(fs *flag.FlagSet, seedFlag int64) int64 {
+	seed := time.Now().UnixNano()
+	fs.Visit(func(f *flag.Flag) {
+		if f.Name == "seed" {
+			seed = seedFlag
+		}
+	})
+	return seed
+}
+
+// parseRatio accepts "1/5" or "0.2".
+func parseRatio(s string) (float64, error) {
+	num, den, isFraction := strings.Cut(s, "/")
+	r, err := strconv.ParseFloat(num, 64)
+	if err == nil && isFraction {
+		var d float64
+		d, err = strconv.ParseFloat(den, 64)
+		r /= d
 	}
-	return "[l]ong cooldown | [q]uit"
+	if err != nil || r <= 0 || math.IsInf(r, 0) {
+		return 0, fmt.Errorf("invalid ratio %q (expected e.g. 1/5 or 0.2)", s)
+	}
+	return r, nil
+}
+
+// formatClock shows a duration as mm:ss (minutes may exceed 59).
+func formatClock(d time.Duration) string {
+	return fmt.Sprintf("%02d:%02d", int(d.Minutes()), int(d.Seconds())%60)
+}
+
+// clickAction maps a left click on a command bar item to its action.
+// Actions are still checked against the current state like key presses.
+func clickAction(v *view.View, cmdInput commandActions, ev input.KeyEvent) keymap.Action {
+	if !ev.IsClick() {
+		return ""
+	}
+	r, row, col, ok := v.RegionAt(ev.Mouse.Y, ev.Mouse.X)
+	if !ok || r != render.Renderable(cmdInput) {
+		return ""
+	}
+	action, _ := cmdInput.ActionAt(row, col)
+	return keymap.Action(action)
+}
+
+// commandActions is the command bar as seen by click handling.
+type commandActions interface {
+	render.Renderable
+	ActionAt(row, col int) (string, bool)
 }
 
 func main() {
-	// Parse optional duration argument (in minutes, decimal allowed)
-	workDuration := 25 * time.Minute
-	if len(os.Args) > 1 {
-		minutes, err := strconv.ParseFloat(os.Args[1], 64)
-		if err != nil {
-			fmt.Fprintf(os.Stderr, "invalid duration: %s (expected minutes, e.g. 25 or 0.2)\n", os.Args[1])
+	if len(os.Args) > 1 && os.Args[1] == "sounds" {
+		if err := runSounds(os.Args[2:]); err != nil {
+			fmt.Fprintf(os.Stderr, "%v\n", err)
+			os.Exit(1)
+		}
+		return
+	}
+	if len(os.Args) > 1 && os.Args[1] == "product" {
+		if err := runProduct(os.Args[2:]); err != nil {
+			fmt.Fprintf(os.Stderr, "%v\n", err)
+			os.Exit(1)
+		}
+		return
+	}
+
+	seedFlag := flag.Int64("seed", 0, "seed for all randomness, to reproduce a session (default: time-based)")
+	keymapFlag := flag.String("keymap", "", "key bindings: a preset (default, vim, emacs) or a keymap file (default: keymap.conf in the config dir)")
+	scheduleFlag := flag.String("schedule", schedule.DefaultName, "work/break cycle: "+strings.Join(schedule.Names(), ", ")+", one from schedules.conf in the config dir, or steps like \"work 45, break 15\"")
+	autoStartFlag := flag.Bool("auto-start", false, "start the next pomodoro 10 seconds after a break ends")
+	autoBreakFlag := flag.Bool("auto-break", false, "skip the celebration and go straight to the break")
+	flowRatioFlag := flag.String("flow-ratio", "1/5", "flowtime break length as a fraction of the focus time")
+	buildStyleFlag := flag.String("build-style", "", "build animation for every product: "+strings.Join(factoryscene.StyleNames(), ", ")+" (default: each product's own)")
+	achievementsFlag := flag.String("achievements", string(status.SummaryRecent), "how a full achievements row is shortened: "+summaryNames())
+	flag.Usage = func() {
+		fmt.Fprintf(flag.CommandLine.Output(), "usage: pomodorofactory [flags] [minutes]\n       pomodorofactory sounds ...\n       pomodorofactory product ...\n\nflags:\n")
+		flag.PrintDefaults()
+	}
+	flag.Parse()
+
+	schedulesPath, _ := configdir.Path("schedules.conf")
+	sched, err := schedule.Lookup(*scheduleFlag, schedulesPath)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "schedule: %v\n", err)
+		os.Exit(1)
+	}
+
+	var buildStyle factoryscene.BuildStyle // overrides the products' styles; nil if not set
+	if *buildStyleFlag != "" {
+		style, ok := factoryscene.StyleByName(*buildStyleFlag)
+		if !ok {
+			fmt.Fprintf(os.Stderr, "unknown build style %q (expected one of: %s)\n", *buildStyleFlag, strings.Join(factoryscene.StyleNames(), ", "))
 			os.Exit(1)
 		}
-		workDuration = time.Duration(minutes * float64(time.Minute))
+		buildStyle = style
+	}
+
+	summary := status.Summary(*achievementsFlag)
+	if !slices.Contains(status.Summaries, summary) {
+		fmt.Fprintf(os.Stderr, "unknown achievements summary %q (expected one of: %s)\n", *achievementsFlag, summaryNames())
+		os.Exit(1)
+	}
+
+	// Optional duration argument (in minutes, decimal allowed) sets every work step
+	if flag.NArg() > 0 {
+		// Flowtime divides by the work length, so it can't be zero
+		minutes, err := strconv.ParseFloat(flag.Arg(0), 64)
+		if err != nil || !validMinutes(minutes) {
+			fmt.Fprintf(os.Stderr, "invalid duration: %s (expected minutes from %g to %d, e.g. 25 or 0.2)\n", flag.Arg(0), minMinutes, maxMinutes)
+			os.Exit(1)
+		}
+		sched = sched.WithWork(time.Duration(minutes * float64(time.Minute)))
+	}
+
+	// Today's build plan; a plan left over from an earlier day is summarized once
+	planPath, _ := configdir.Path("plan.json")
+	dayPlan, err := plan.Load(planPath, today())
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "plan: %v\n", err)
+		os.Exit(1)
+	}
+	finishedDay := dayPlan.Rollover(today())
+	if finishedDay != nil {
+		if err := dayPlan.Save(planPath); err != nil {
+			fmt.Fprintf(os.Stderr, "plan: %v\n", err)
+			os.Exit(1)
+		}
+	}
+
+	// Finished sections of mega-builds carry over between sessions
+	megaPath, _ := configdir.Path("megabuilds.json")
+	megaProgress, err := megabuild.Load(megaPath)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "megabuilds: %v\n", err)
+		os.Exit(1)
+	}
+
+	// Everything ever built, for the warehouse
+	stockPath, _ := configdir.Path("warehouse.json")
+	stock, err := warehouse.Load(stockPath)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "warehouse: %v\n", err)
+		os.Exit(1)
+	}
+
+	if err := loadCustomProducts(); err != nil {
+		fmt.Fprintf(os.Stderr, "products: %v\n", err)
+		os.Exit(1)
+	}
+
+	// Lifetime counters unlock products and factory upgrades
+	countersPath, _ := configdir.Path("progress.json")
+	counters, found, err := progression.Load(countersPath)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "progress: %v\n", err)
+		os.Exit(1)
+	}
+	if !found {
+		seedCounters(counters, stock)
+	}
+
+	flowRatio, err := parseRatio(*flowRatioFlag)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "flow-ratio: %v\n", err)
+		os.Exit(1)
+	}
+
+	km, err := loadKeymap(*keymapFlag)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "keymap: %v\n", err)
+		os.Exit(1)
+	}
+
+	// One seeded source drives every random choice (messages, phrases, sparks,
+	// sounds), so a session can be replayed with --seed
+	seed := chooseSeed(flag.CommandLine, *seedFlag)
+	rng := rand.New(rand.NewSource(seed))
+	// Deferred first so it prints after the terminal is restored
+	defer fmt.Printf("seed: %d (replay with --seed %d)\n", seed, seed)
+
+	// User sound pack (optional — anything missing uses the built-in sound).
+	// Broken files are reported but don't stop the factory
+	var sounds *audio.SoundPack
+	if dir, err := configdir.Path("sounds"); err == nil {
+		if sounds, err = audio.LoadSoundPack(dir); err != nil {
+			fmt.Fprintf(os.Stderr, "sounds: %v\n", err)
+		}
 	}
 
 	// Put terminal in raw mode
@@ -122,64 +485,133 @@ func main() {
 	fmt.Print("\033[?25l")
 	defer fmt.Print("\033[?25h")
 
+	// Bracketed paste, so pasted text arrives as one event instead of keystrokes
+	fmt.Print("\033[?2004h")
+	defer fmt.Print("\033[?2004l")
+
+	// SGR mouse reporting, so the command bar can be clicked
+	fmt.Print("\033[?1000h\033[?1006h")
+	defer fmt.Print("\033[?1000l\033[?1006l")
+
 	// Initialize audio (optional — celebration works visually without it)
 	audioEngine, _ := audio.NewEngine()
 
 	// Product selection state
 	products := product.All
-	selectedProductIdx := 0
+	selectedProductIdx := plannedProduct(dayPlan, products, 0)
 	achievedEmojis := []string{}
 
 	// Build components
-	factory := factoryscene.MakeFactoryScene(products)
-	motivationcloudComp := motivationcloud.MakeMotivationcloud()
+	factory := factoryscene.MakeFactoryScene(products, rng)
+	motivationcloudComp := motivationcloud.MakeMotivationcloud(rng)
 	statusComp := status.MakeStatus()
+	_, setSize := sched.Position(0)
+	statusComp.SetSummary(summary, setSize)
 	cmdInput := commandinput.MakeCommandinput()
-	cmdInput.SetTexts("[s]tart | [q]uit", selectorLine(products, selectedProductIdx))
+	cmdInput.SetCompleter(completeCommand(products))
+	cmdInput.SetItems(idleCmdItems(km, false), selectorItems(km, products, selectedProductIdx, dayPlan, counters))
+	statusComp.SetAchievements(idleStatusText(km), achievedEmojis)
 	v := view.MakeView(factory, motivationcloudComp, statusComp, cmdInput)
+	help := helpoverlay.MakeHelpOverlay()
+	planView := helpoverlay.MakeHelpOverlay()
+	summaryView := helpoverlay.MakeHelpOverlay()
+	warehouseView := warehouse.MakeWarehouseView(stock)
+	milestoneView := helpoverlay.MakeHelpOverlay()
+	if finishedDay != nil {
+		summaryView.SetContent(daySummary(finishedDay))
+		v.SetOverlay(summaryView)
+	}
 
-	t := timer.NewTimer(workDuration)
-	celeb := celebration.New(audioEngine)
+	step := 0 // index into sched.Steps of the running or next step
+	t := timer.NewTimer(sched.Steps[step].Duration)
+	celeb := celebration.New(audioEngine, sounds, rng)
 	lastShuffle := time.Now()
 
 	state := stateIdle
 	congratsMsg := ""
 	isLongBreak := false
+	flowing := false               // the current or last session is flowtime
+	focusTime := time.Duration(0)  // length of the last flowtime session
+	var autoStartAt time.Time      // when the pending auto-start fires; zero if none
+	grandFinale := false           // this celebration finishes a mega-build
+	workedTime := time.Duration(0) // length of the last finished work session
 
-	// Read input in a goroutine; arrow keys are decoded as sentinel rune values
-	inputCh := make(chan rune)
-	go func() {
-		buf := make([]byte, 1)
-		for {
-			n, err := os.Stdin.Read(buf)
-			if n > 0 {
-				b := buf[0]
-				if b == 0x1b {
-					// Possible escape sequence — read up to two more bytes
-					seq := make([]byte, 2)
-					n2, _ := os.Stdin.Read(seq[:1])
-					if n2 > 0 && seq[0] == '[' {
-						n3, _ := os.Stdin.Read(seq[1:2])
-						if n3 > 0 {
-							switch seq[1] {
-							case 'C':
-								inputCh <- keyRight
-							case 'D':
-								inputCh <- keyLeft
-							}
-						}
-					}
-					// lone ESC or unrecognised sequence: ignored
-				} else {
-					inputCh <- rune(b)
-				}
-			}
-			if err != nil {
-				close(inputCh)
-				return
-			}
+	applyUpgrades := func() {
+		factory.SetUpgrades(factoryUpgrades(counters))
+		if counters.Has(progression.SteelBorder) {
+			v.SetBorderColor(steelBorder)
+		}
+	}
+	applyUpgrades()
+
+	// showProduct loads the selected product into the factory; a mega-build
+	// shows the sections finished so far and welds the next one.
+	shownProduct := -1
+	showProduct := func() {
+		p := products[selectedProductIdx]
+		style := buildStyle
+		if style == nil {
+			style, _ = factoryscene.StyleByName(p.Style)
 		}
-	}()
+		factory.SetStyle(style)
+		factory.LoadArt(p.Art)
+		factory.SetAnimation(p.Frames)
+		factory.SetCelebration(p.Celebration.Sparks, p.Celebration.Colors)
+		if _, locked := counters.LockedBy(p.Name); locked {
+			factory.SetSilhouette(true)
+		} else if p.IsMega() {
+			factory.SetSection(megaProgress.Done(p.Name, p.Sessions), p.Sessions)
+		}
+		shownProduct = selectedProductIdx
+	}
+
+	// The art editor saves to the products folder and puts the product
+	// into the rotation right away
+	artEditor := arteditor.MakeEditor(func(c *product.Custom) error {
+		if len(c.Art) == 0 {
+			return fmt.Errorf("nothing drawn yet")
+		}
+		p, err := c.Product()
+		if err != nil {
+			return err
+		}
+		dir, err := configdir.Path("products")
+		if err != nil {
+			return err
+		}
+		if _, err := c.Save(dir); err != nil {
+			return err
+		}
+		idx := slices.IndexFunc(products, func(q *product.Product) bool { return q.Name == p.Name })
+		if idx < 0 {
+			products = append(products, p)
+			idx = len(products) - 1
+		}
+		products[idx] = p
+		selectedProductIdx = idx
+		shownProduct = -1
+		cmdInput.SetCompleter(completeCommand(products))
+		cmdInput.SetItems(idleCmdItems(km, !autoStartAt.IsZero()), selectorItems(km, products, selectedProductIdx, dayPlan, counters))
+		return nil
+	})
+
+	// startable reports whether the selected product is unlocked, and
+	// tells what's missing if it isn't
+	startable := func() bool {
+		p := products[selectedProductIdx]
+		m, locked := counters.LockedBy(p.Name)
+		if locked {
+			autoStartAt = time.Time{}
+			cmdInput.SetMessage(lockedText(p.Name, m, counters), true)
+			cmdInput.SetItems(idleCmdItems(km, false), selectorItems(km, products, selectedProductIdx, dayPlan, counters))
+			statusComp.SetAchievements(idleStatusText(km), achievedEmojis)
+		}
+		return !locked
+	}
+	showProduct()
+
+	// Decode stdin into key events (arrows, function keys, Alt combos, paste…)
+	keyEvents := input.NewDecoder(os.Stdin).Events()
 
 	// Initial render
 	v.Render()
@@ -191,89 +623,312 @@ func main() {
 	// Event loop
 	for {
 		dirty := false
+		var action keymap.Action
+		var amount time.Duration // from the command line, e.g. ":start penguin 40"
 
 		select {
-		case b, ok := <-inputCh:
+		case ev, ok := <-keyEvents:
 			if !ok {
 				return
 			}
 			dirty = true
-			switch b {
-			case 'q', 0x03: // 'q' or Ctrl+C
-				return
-			case 'h', keyLeft:
-				if state == stateIdle {
-					selectedProductIdx = (selectedProductIdx - 1 + len(products)) % len(products)
-					cmdInput.SetTexts("[s]tart | [q]uit", selectorLine(products, selectedProductIdx))
-				}
-			case 'l', keyRight:
-				if state == stateIdle {
-					selectedProductIdx = (selectedProductIdx + 1) % len(products)
-					cmdInput.SetTexts("[s]tart | [q]uit", selectorLine(products, selectedProductIdx))
-				} else if b == 'l' && state == stateOnBreak && !isLongBreak {
-					isLongBreak = true
-					t.Reset(longBreak)
-					t.Start()
-					cmdInput.SetTexts(breakCmdText(isLongBreak), "")
-				}
-			case 's':
-				if state == stateIdle {
-					state = stateWorking
-					factory.LoadArt(products[selectedProductIdx].Art)
-					t.Reset(workDuration)
-					t.Start()
-					factory.Reset()
-					cmdInput.SetTexts("e[x]it current pomodoro | [q]uit", "")
-				} else if state == stateOnBreak && isLongBreak {
-					isLongBreak = false
-					t.Reset(shortBreak)
-					t.Start()
-					cmdInput.SetTexts(breakCmdText(isLongBreak), "")
-				}
-			case 'x':
-				if state == stateWorking {
-					state = stateIdle
-					t.Reset(workDuration)
-					factory.Reset()
-					statusComp.SetAchievements("Factory ready  press [s] to start", achievedEmojis)
-					cmdInput.SetTexts("[s]tart | [q]uit", selectorLine(products, selectedProductIdx))
-				}
-			case 'c':
-				if state == stateWaitingForCelebration {
-					state = stateCelebrating
-					congratsMsg = randomCongrats(products[selectedProductIdx].Name)
-					celeb.Start(congratsMsg)
+			if v.HasOverlay() {
+				// The warehouse scrolls and the editor takes every key; otherwise
+				// any key or click closes the overlay
+				if v.Overlay() == render.Renderable(warehouseView) && warehouseView.HandleKey(ev) {
+					break
+				}
+				if v.Overlay() == render.Renderable(artEditor) {
+					if !artEditor.HandleKey(ev) {
+						v.ClearOverlay()
+					}
+					break
+				}
+				if ev.Key != input.KeyMouse || ev.IsClick() {
+					v.ClearOverlay()
+				}
+				break
+			}
+			switch {
+			case cmdInput.Prompting():
+				line, ok := cmdInput.HandleKey(ev)
+				if !ok || line == "" {
+					break
+				}
+				cmd, err := parseCommand(line, products, state)
+				if err != nil {
+					cmdInput.SetMessage(err.Error(), true)
+					break
+				}
+				if cmd.product >= 0 {
+					selectedProductIdx = cmd.product
+					cmdInput.SetItems(idleCmdItems(km, !autoStartAt.IsZero()), selectorItems(km, products, selectedProductIdx, dayPlan, counters))
+				}
+				if cmd.edit != "" {
+					c, err := customToEdit(cmd.edit, products)
+					if err != nil {
+						cmdInput.SetMessage(err.Error(), true)
+						break
+					}
+					if err := artEditor.Open(c); err != nil {
+						cmdInput.SetMessage(err.Error(), true)
+						break
+					}
+					autoStartAt = time.Time{}
+					v.SetOverlay(artEditor)
+				}
+				if cmd.planEdit != nil {
+					err := cmd.planEdit(dayPlan)
+					if err == nil {
+						err = dayPlan.Save(planPath)
+					}
+					if err != nil {
+						cmdInput.SetMessage(err.Error(), true)
+						break
+					}
+					if state == stateIdle {
+						selectedProductIdx = plannedProduct(dayPlan, products, selectedProductIdx)
+						cmdInput.SetItems(idleCmdItems(km, !autoStartAt.IsZero()), selectorItems(km, products, selectedProductIdx, dayPlan, counters))
+					}
 				}
+				amount = cmd.duration
+				action = cmd.action
+			case ev.Key == input.KeyMouse:
+				action = clickAction(v, cmdInput, ev)
+			default:
+				cmdInput.ClearMessage()
+				action, _ = km.Action(state.keyContext(), ev.String())
 			}
 
 		case <-ticker.C:
 			// tick proceeds — let state and cloud determine if a redraw is needed
+			if v.Overlay() == render.Renderable(artEditor) {
+				artEditor.Tick()
+				dirty = true
+			}
+			if state == stateIdle && !autoStartAt.IsZero() && !time.Now().Before(autoStartAt) {
+				action = keymap.ActionStart
+			}
+		}
+
+		switch action {
+		case keymap.ActionQuit:
+			return
+		case keymap.ActionCommand:
+			cmdInput.OpenPrompt()
+		case keymap.ActionHelp:
+			v.SetOverlay(help)
+		case keymap.ActionPlan:
+			v.SetOverlay(planView)
+		case keymap.ActionWarehouse:
+			warehouseView.Refresh()
+			v.SetOverlay(warehouseView)
+		case keymap.ActionPrevProduct:
+			if state == stateIdle {
+				selectedProductIdx = (selectedProductIdx - 1 + len(products)) % len(products)
+				cmdInput.SetItems(idleCmdItems(km, !autoStartAt.IsZero()), selectorItems(km, products, selectedProductIdx, dayPlan, counters))
+			}
+		case keymap.ActionNextProduct:
+			if state == stateIdle {
+				selectedProductIdx = (selectedProductIdx + 1) % len(products)
+				cmdInput.SetItems(idleCmdItems(km, !autoStartAt.IsZero()), selectorItems(km, products, selectedProductIdx, dayPlan, counters))
+			}
+		case keymap.ActionLongBreak:
+			if state == stateIdle || state == stateOnBreak && !isLongBreak {
+				state = stateOnBreak
+				isLongBreak = true
+				t.Reset(sched.LongBreak())
+				t.Start()
+				cmdInput.SetItems(breakCmdItems(km, isLongBreak), nil)
+			}
+		case keymap.ActionStart:
+			if state == stateIdle && startable() {
+				state = stateWorking
+				flowing = false
+				autoStartAt = time.Time{}
+				showProduct()
+				duration := sched.Steps[step].Duration
+				if amount > 0 {
+					duration = amount
+				}
+				t.Reset(duration)
+				t.Start()
+				factory.Reset()
+				cmdInput.SetItems(workCmdItems(km, flowing), nil)
+			}
+		case keymap.ActionFlow:
+			if state == stateIdle && startable() {
+				state = stateWorking
+				flowing = true
+				autoStartAt = time.Time{}
+				showProduct()
+				t.ResetStopwatch()
+				t.Start()
+				factory.Reset()
+				cmdInput.SetItems(workCmdItems(km, flowing), nil)
+			}
+		case keymap.ActionFinish:
+			if state == stateWorking && flowing {
+				focusTime = t.Elapsed()
+				t.Finish()
+			}
+		case keymap.ActionShortBreak:
+			if state == stateIdle || state == stateOnBreak && isLongBreak {
+				state = stateOnBreak
+				isLongBreak = false
+				t.Reset(sched.ShortBreak())
+				t.Start()
+				cmdInput.SetItems(breakCmdItems(km, isLongBreak), nil)
+			}
+		case keymap.ActionExtend, keymap.ActionShorten:
+			if state == stateWorking || state == stateOnBreak {
+				step := timerStep
+				if amount != 0 {
+					step = amount
+				}
+				if action == keymap.ActionShorten {
+					step = -step
+				}
+				t.Extend(step)
+			}
+		case keymap.ActionSkipBreak:
+			if state == stateOnBreak {
+				state = stateIdle
+				if sched.Steps[step].Kind == schedule.Break {
+					step = sched.Next(step)
+				}
+				factory.Reset()
+				statusComp.SetAchievements(idleStatusText(km), achievedEmojis)
+				if *autoStartFlag {
+					autoStartAt = time.Now().Add(autoStartCountdown)
+				}
+				selectedProductIdx = plannedProduct(dayPlan, products, selectedProductIdx)
+				cmdInput.SetItems(idleCmdItems(km, *autoStartFlag), selectorItems(km, products, selectedProductIdx, dayPlan, counters))
+			}
+		case keymap.ActionCancel:
+			if state == stateIdle && !autoStartAt.IsZero() {
+				autoStartAt = time.Time{}
+				statusComp.SetAchievements(idleStatusText(km), achievedEmojis)
+				cmdInput.SetItems(idleCmdItems(km, !autoStartAt.IsZero()), selectorItems(km, products, selectedProductIdx, dayPlan, counters))
+			}
+		case keymap.ActionAbort:
+			if state == stateWorking {
+				state = stateIdle
+				t.Reset(sched.Steps[step].Duration)
+				factory.Reset()
+				statusComp.SetAchievements(idleStatusText(km), achievedEmojis)
+				cmdInput.SetItems(idleCmdItems(km, !autoStartAt.IsZero()), selectorItems(km, products, selectedProductIdx, dayPlan, counters))
+			}
+		case keymap.ActionCelebrate:
+			if state == stateWaitingForCelebration {
+				state = stateCelebrating
+				p := products[selectedProductIdx]
+				voice, _ := audio.VoiceByName(p.Voice)
+				if p.Celebration.Pitch > 0 {
+					voice.Pitch *= p.Celebration.Pitch
+				}
+				var fanfare []byte
+				if p.Celebration.Jingle != "" {
+					var err error
+					if fanfare, err = audio.RenderJingle(p.Celebration.Jingle); err != nil {
+						cmdInput.SetMessage(fmt.Sprintf("%s jingle: %v", p.Name, err), true)
+					}
+				}
+				built := p.Name
+				grandFinale = false
+				if p.IsMega() {
+					section := megaProgress.Done(p.Name, p.Sessions) + 1
+					grandFinale = section == p.Sessions
+					if !grandFinale {
+						built = fmt.Sprintf("section %d of %d of the %s", section, p.Sessions, p.Name)
+					}
+				}
+				congratsMsg = randomCongrats(rng, built, p.Celebration)
+				if grandFinale {
+					celeb.StartGrand(congratsMsg, voice, fanfare)
+				} else {
+					celeb.Start(congratsMsg, voice, fanfare)
+				}
+			}
 		}
 
 		switch state {
+		case stateIdle:
+			if shownProduct != selectedProductIdx {
+				dirty = true
+				showProduct()
+			}
+			// Past midnight: summarize the finished day and start a fresh plan
+			if finished := dayPlan.Rollover(today()); finished != nil {
+				dirty = true
+				summaryView.SetContent(daySummary(finished))
+				v.SetOverlay(summaryView)
+				if err := dayPlan.Save(planPath); err != nil {
+					cmdInput.SetMessage("plan: "+err.Error(), true)
+				}
+				cmdInput.SetItems(idleCmdItems(km, !autoStartAt.IsZero()), selectorItems(km, products, selectedProductIdx, dayPlan, counters))
+			}
+			if !autoStartAt.IsZero() {
+				dirty = true
+				secs := int(time.Until(autoStartAt).Seconds() + 0.999)
+				statusComp.SetAchievements(
+					fmt.Sprintf("Next pomodoro in %ds  press [%s] to cancel", secs, km.KeyLabel(keymap.ContextIdle, keymap.ActionCancel)),
+					achievedEmojis,
+				)
+			}
+
 		case stateWorking:
 			dirty = true
-			factory.SetProgress(t.Progress())
-			remaining := t.Remaining()
-			mins := int(remaining.Minutes())
-			secs := int(remaining.Seconds()) % 60
-			statusComp.SetAchievements(
-				fmt.Sprintf("Factory running  %02d:%02d", mins, secs),
-				achievedEmojis,
-			)
+			if flowing && !t.IsFinished() {
+				// The product is rebuilt once per work step length while the flow lasts
+				elapsed := t.Elapsed()
+				pieceDuration := max(sched.Steps[step].Duration, time.Second)
+				factory.SetProgress(float64(elapsed%pieceDuration) / float64(pieceDuration))
+				text := fmt.Sprintf("Flowing  %s", formatClock(elapsed))
+				if built := int(elapsed / pieceDuration); built > 0 {
+					text += fmt.Sprintf("  %d built", built)
+				}
+				statusComp.SetAchievements(text, achievedEmojis)
+			} else if !flowing {
+				factory.SetProgress(t.Progress())
+				text := fmt.Sprintf("Factory running  %s", formatClock(t.Remaining()))
+				if p := products[selectedProductIdx]; p.IsMega() {
+					text += fmt.Sprintf("  section %d/%d", megaProgress.Done(p.Name, p.Sessions)+1, p.Sessions)
+				}
+				statusComp.SetAchievements(text, achievedEmojis)
+			}
 
 			if t.IsFinished() {
 				state = stateWaitingForCelebration
+				workedTime = t.Duration()
+				if flowing {
+					workedTime = focusTime
+				}
 				factory.SetProgress(1.0)
-				statusComp.SetAchievements("Pomodoro done!  Press [c] to celebrate", achievedEmojis)
-				cmdInput.SetTexts("[c]elebrate", "")
+				doneText := "Pomodoro done!"
+				if flowing {
+					doneText = fmt.Sprintf("%s of focus!", formatClock(focusTime))
+				}
+				statusComp.SetAchievements(
+					fmt.Sprintf("%s  Press [%s] to celebrate", doneText, km.KeyLabel(keymap.ContextWaiting, keymap.ActionCelebrate)),
+					achievedEmojis,
+				)
+				cmdInput.SetItems(commandItems(km, keymap.ContextWaiting, keymap.ActionCelebrate), nil)
 				if audioEngine != nil {
-					audioEngine.Play(audio.MakeNotificationSound())
+					audioEngine.Play(sounds.Bell())
+				}
+				if *autoBreakFlag {
+					// No celebration is started, so the celebrating state
+					// records the product and starts the break on the next tick
+					state = stateCelebrating
 				}
 			}
 
 		case stateWaitingForCelebration:
-			// nothing to update each tick — dirty stays false unless cloud animates
+			// Only an animated product changes while waiting
+			if factory.Animate() {
+				dirty = true
+			}
 
 		case stateCelebrating:
 			dirty = true
@@ -281,47 +936,96 @@ func main() {
 				phase := celeb.Tick()
 				switch phase {
 				case celebration.PhaseParty:
-					factory.SetCelebrating(celeb.PartyTick())
-					statusComp.SetCelebrationText("POMODORO COMPLETE!", celeb.PartyTick())
+					factory.SetCelebrating(celeb.PartyTick(), grandFinale)
+					statusComp.SetCelebrationText(celebrationTitle(products[selectedProductIdx], megaProgress, grandFinale), celeb.PartyTick())
 				case celebration.PhaseSpeech:
 					factory.SetProgress(1.0)
+					factory.Animate()
 					statusComp.SetSpeechText(congratsMsg, celeb.CurrentCharIndex())
 				}
 			} else {
 				// Celebration finished — record achievement and auto-start break
-				achievedEmojis = append(achievedEmojis, products[selectedProductIdx].Emoji)
-				isLongBreak = len(achievedEmojis)%pomodorosPerSet == 0
-				breakDuration := shortBreak
-				if isLongBreak {
-					breakDuration = longBreak
+				// A mega-build counts as built once its last section is welded
+				p := products[selectedProductIdx]
+				built := true
+				if p.IsMega() {
+					built = megaProgress.Advance(p.Name, p.Sessions)
+					if err := megaProgress.Save(megaPath); err != nil {
+						cmdInput.SetMessage("megabuilds: "+err.Error(), true)
+					}
+					shownProduct = -1 // show the new section when idle again
+				}
+				if built {
+					achievedEmojis = append(achievedEmojis, p.Emoji)
+					stock.Add(p.Name, p.Emoji, time.Now())
+					if err := stock.Save(stockPath); err != nil {
+						cmdInput.SetMessage("warehouse: "+err.Error(), true)
+					}
+					dayPlan.Record(p.Name)
+					if err := dayPlan.Save(planPath); err != nil {
+						cmdInput.SetMessage("plan: "+err.Error(), true)
+					}
+				}
+				reachedBefore := counters.Reached()
+				counters.Focus(workedTime, today())
+				if built {
+					counters.Build(p.Name)
+				}
+				if err := counters.Save(countersPath); err != nil {
+					cmdInput.SetMessage("progress: "+err.Error(), true)
+				}
+				if reached := newlyReached(reachedBefore, counters.Reached()); len(reached) > 0 {
+					milestoneView.SetContent(milestoneContent(reached))
+					v.SetOverlay(milestoneView)
+					applyUpgrades()
+					shownProduct = -1
+				}
+				step = sched.Next(step)
+				breakDuration := sched.Steps[step].Duration
+				isLongBreak = breakDuration > sched.ShortBreak()
+				if flowing {
+					// Flowtime earns a break proportional to the focus time
+					isLongBreak = false
+					breakDuration = time.Duration(float64(focusTime) * flowRatio).Round(time.Second)
 				}
 				state = stateOnBreak
 				t.Reset(breakDuration)
 				t.Start()
 				factory.SetProgress(1.0)
-				cmdInput.SetTexts(breakCmdText(isLongBreak), "")
+				cmdInput.SetItems(breakCmdItems(km, isLongBreak), nil)
 			}
 
 		case stateOnBreak:
 			dirty = true
 			t.Progress() // drive the finished flag
-			remaining := t.Remaining()
-			mins := int(remaining.Minutes())
-			secs := int(remaining.Seconds()) % 60
+			factory.Animate()
 			label := "Factory needs a short cooldown"
 			if isLongBreak {
 				label = "Factory needs a longer cooldown"
+			} else if flowing {
+				label = fmt.Sprintf("Cooldown after %s of focus", formatClock(focusTime))
 			}
 			statusComp.SetAchievements(
-				fmt.Sprintf("%s  %02d:%02d", label, mins, secs),
+				fmt.Sprintf("%s  %s", label, formatClock(t.Remaining())),
 				achievedEmojis,
 			)
 
 			if t.IsFinished() {
 				state = stateIdle
+				// A break taken with :break from idle doesn't move through the schedule
+				if sched.Steps[step].Kind == schedule.Break {
+					step = sched.Next(step)
+				}
+				if audioEngine != nil {
+					audioEngine.Play(sounds.BreakEnd())
+				}
 				factory.Reset()
-				statusComp.SetAchievements("Factory ready  press [s] to start", achievedEmojis)
-				cmdInput.SetTexts("[s]tart | [q]uit", selectorLine(products, selectedProductIdx))
+				statusComp.SetAchievements(idleStatusText(km), achievedEmojis)
+				if *autoStartFlag {
+					autoStartAt = time.Now().Add(autoStartCountdown)
+				}
+				selectedProductIdx = plannedProduct(dayPlan, products, selectedProductIdx)
+				cmdInput.SetItems(idleCmdItems(km, *autoStartFlag), selectorItems(km, products, selectedProductIdx, dayPlan, counters))
 			}
 		}
 
@@ -335,8 +1039,24 @@ func main() {
 		}
 
 		if dirty {
+			n, total := sched.Position(step)
+			statusComp.SetPosition(fmt.Sprintf("%s %d/%d", sched.Name, n, total))
+			switch v.Overlay() {
+			case render.Renderable(help):
+				help.SetContent(helpContent(km, state, sched, step, flowRatio, len(achievedEmojis)))
+			case render.Renderable(planView):
+				planView.SetContent(planContent(dayPlan))
+			}
 			v.Render()
 			v.Print()
 		}
 	}
 }
+
+func summaryNames() string {
+	names := make([]string, len(status.Summaries))
+	for i, s := range status.Summaries {
+		names[i] = string(s)
+	}
+	return strings.Join(names, ", ")
+}
@@ -0,0 +1,357 @@
+package arteditor
+
+import (
+	"fmt"
+	"math/rand"
+	"strings"
+
+	"github.com/anschnapp/pomodorofactory/pkg/factoryscene"
+	"github.com/anschnapp/pomodorofactory/pkg/input"
+	"github.com/anschnapp/pomodorofactory/pkg/product"
+	"github.com/anschnapp/pomodorofactory/pkg/render"
+	"github.com/anschnapp/pomodorofactory/pkg/runecolor"
+	"github.com/fatih/color"
+)
+
+// The size products are drawn for; bigger art grows the factory canvas.
+const (
+	gridWidth  = 23
+	gridHeight = 10
+)
+
+const (
+	previewGap    = 3   // columns between the grid and the preview
+	previewTicks  = 120 // ticks (50ms) the preview takes to build the art
+	previewLinger = 30  // ticks the finished preview stays before starting over
+)
+
+// Palette is what cells can be colored with, in RRGGBB; product.NoColor
+// leaves a cell in the terminal's default color.
+var Palette = []string{
+	product.NoColor,
+	"ffffff", "a0a0a0", "505050",
+	"ff4040", "ffa500", "ffe040",
+	"40c040", "20a0a0", "6495ed",
+	"3050d0", "c060ff", "ff80c0",
+	"8b5a2b", "dcbe6e",
+}
+
+var (
+	titleColor  = []color.Attribute{color.Bold, color.FgHiYellow}
+	faintColor  = []color.Attribute{color.Faint}
+	cursorColor = []color.Attribute{color.ReverseVideo}
+	errorColor  = []color.Attribute{color.FgHiRed}
+	savedColor  = []color.Attribute{color.FgHiGreen}
+)
+
+const help1 = "type to draw  arrows move  tab color  ctrl+p paint"
+const help2 = "shift+tab previous color  ctrl+s save  esc close"
+
+type cell struct {
+	glyph rune
+	color string // RRGGBB or product.NoColor
+}
+
+type editor struct {
+	custom  *product.Custom
+	cells   [gridHeight][gridWidth]cell
+	row     int
+	col     int
+	paint   int // index into Palette
+	dirty   bool
+	closing bool // esc was pressed with unsaved changes
+	message string
+	isError bool
+
+	save    func(*product.Custom) error
+	preview scene
+	tick    int
+}
+
+// scene is the part of the factory the preview uses.
+type scene interface {
+	render.Renderable
+	SetStyle(style factoryscene.BuildStyle)
+	LoadArt(art [][]runecolor.ColoredRune)
+	SetProgress(p float64)
+}
+
+// MakeEditor makes the art editor screen. save is called with the product
+// when the user saves it.
+func MakeEditor(save func(*product.Custom) error) *editor {
+	blank := make([][]runecolor.ColoredRune, gridHeight)
+	for i := range blank {
+		blank[i] = runecolor.ConvertSimpleRunes([]rune(strings.Repeat(" ", gridWidth)))
+	}
+	placeholder := &product.Product{Art: blank}
+	return &editor{
+		save:    save,
+		preview: factoryscene.MakeFactoryScene([]*product.Product{placeholder}, rand.New(rand.NewSource(1))),
+	}
+}
+
+// Open starts editing c, which may be new with no art yet. Art larger than
+// the grid is refused rather than cropped, so saving can't lose any of it.
+func (e *editor) Open(c *product.Custom) error {
+	width := 0
+	for _, line := range c.Art {
+		width = max(width, len([]rune(line)))
+	}
+	if width > gridWidth || len(c.Art) > gridHeight {
+		return fmt.Errorf("%s is %d×%d, larger than the editor's %d×%d", c.Name, width, len(c.Art), gridWidth, gridHeight)
+	}
+	e.custom = c
+	e.row, e.col = 0, 0
+	e.paint = 1
+	e.dirty, e.closing = false, false
+	e.message, e.isError = "", false
+	for r := range e.cells {
+		for col := range e.cells[r] {
+			e.cells[r][col] = cell{' ', product.NoColor}
+		}
+	}
+	for r, line := range c.Art {
+		var colors []string
+		if r < len(c.Colors) {
+			colors = strings.Fields(c.Colors[r])
+		}
+		for col, glyph := range []rune(line) {
+			e.cells[r][col].glyph = glyph
+			if col < len(colors) {
+				e.cells[r][col].color = colors[col]
+			}
+		}
+	}
+	e.updatePreview()
+	return nil
+}
+
+// HandleKey edits the art. It returns false when the editor should close.
+func (e *editor) HandleKey(ev input.KeyEvent) bool {
+	e.message = ""
+	closing := e.closing
+	e.closing = false
+	switch {
+	case ev.Key == input.KeyEscape:
+		if e.dirty && !closing {
+			e.closing = true
+			e.setMessage("unsaved changes: esc again to discard, ctrl+s to save", true)
+			return true
+		}
+		return false
+	case ev.String() == "ctrl+s":
+		if err := e.save(e.Custom()); err != nil {
+			e.setMessage(err.Error(), true)
+			break
+		}
+		e.dirty = false
+		e.setMessage("saved "+e.custom.Name, false)
+	case ev.String() == "ctrl+p":
+		e.cells[e.row][e.col].color = Palette[e.paint]
+		e.changed()
+	case ev.Key == input.KeyTab && ev.Mod&input.ModShift != 0:
+		e.paint = (e.paint + len(Palette) - 1) % len(Palette)
+	case ev.Key == input.KeyTab:
+		e.paint = (e.paint + 1) % len(Palette)
+	case ev.Key == input.KeyUp:
+		e.move(-1, 0)
+	case ev.Key == input.KeyDown:
+		e.move(1, 0)
+	case ev.Key == input.KeyLeft:
+		e.move(0, -1)
+	case ev.Key == input.KeyRight:
+		e.move(0, 1)
+	case ev.Key == input.KeyHome:
+		e.col = 0
+	case ev.Key == input.KeyEnd:
+		e.col = gridWidth - 1
+	case ev.Key == input.KeyEnter:
+		e.row, e.col = min(e.row+1, gridHeight-1), 0
+	case ev.Key == input.KeyBackspace:
+		e.move(0, -1)
+		e.put(' ')
+	case ev.Key == input.KeyDelete:
+		e.put(' ')
+	case ev.Key == input.KeyPaste:
+		startCol := e.col
+		for i, line := range strings.Split(ev.Paste, "\n") {
+			if i > 0 {
+				e.row, e.col = min(e.row+1, gridHeight-1), startCol
+			}
+			for _, r := range strings.TrimRight(line, "\r") {
+				e.draw(r)
+			}
+		}
+	case ev.Key == input.KeyRune && ev.Mod&(input.ModCtrl|input.ModAlt) == 0:
+		e.draw(ev.Rune)
+	}
+	return true
+}
+
+// draw puts a glyph at the cursor and moves right.
+func (e *editor) draw(r rune) {
+	if r == '\t' {
+		r = ' '
+	}
+	e.put(r)
+	e.move(0, 1)
+}
+
+// put sets the glyph under the cursor; drawn glyphs take the selected
+// color, erased cells lose theirs.
+func (e *editor) put(r rune) {
+	c := cell{r, Palette[e.paint]}
+	if r == ' ' {
+		c.color = product.NoColor
+	}
+	e.cells[e.row][e.col] = c
+	e.changed()
+}
+
+func (e *editor) move(rows, cols int) {
+	e.row = min(max(e.row+rows, 0), gridHeight-1)
+	e.col = min(max(e.col+cols, 0), gridWidth-1)
+}
+
+func (e *editor) changed() {
+	e.dirty = true
+	e.updatePreview()
+}
+
+func (e *editor) setMessage(text string, isError bool) {
+	e.message, e.isError = text, isError
+}
+
+// Custom returns the product as drawn so far, with blank rows and columns
+// at the end trimmed.
+func (e *editor) Custom() *product.Custom {
+	c := *e.custom
+	c.Art, c.Colors = nil, nil
+	for _, row := range e.cells {
+		var line strings.Builder
+		colors := make([]string, 0, gridWidth)
+		for _, cl := range row {
+			line.WriteRune(cl.glyph)
+			colors = append(colors, cl.color)
+		}
+		art := strings.TrimRight(line.String(), " ")
+		c.Art = append(c.Art, art)
+		c.Colors = append(c.Colors, strings.Join(colors[:len([]rune(art))], " "))
+	}
+	for len(c.Art) > 0 && c.Art[len(c.Art)-1] == "" {
+		c.Art, c.Colors = c.Art[:len(c.Art)-1], c.Colors[:len(c.Colors)-1]
+	}
+	return &c
+}
+
+func (e *editor) updatePreview() {
+	p, err := e.Custom().Product()
+	if err != nil {
+		return
+	}
+	if len(p.Art) == 0 {
+		p.Art = [][]runecolor.ColoredRune{{}}
+	}
+	style, _ := factoryscene.StyleByName(p.Style)
+	e.preview.SetStyle(style)
+	e.preview.LoadArt(p.Art)
+	e.preview.SetProgress(e.previewProgress())
+}
+
+// Tick runs the live preview: the crane builds the art over and over.
+func (e *editor) Tick() {
+	e.tick = (e.tick + 1) % (previewTicks + previewLinger)
+	e.preview.SetProgress(e.previewProgress())
+}
+
+func (e *editor) previewProgress() float64 {
+	return min(float64(e.tick)/previewTicks, 1)
+}
+
+func (e *editor) Width() int {
+	return gridWidth + previewGap + e.preview.Width()
+}
+
+func (e *editor) Height() int {
+	return 2 + max(gridHeight, e.preview.Height()) + 5
+}
+
+func (e *editor) Render(subview [][]runecolor.ColoredRune) {
+	for i := range subview {
+		for j := range subview[i] {
+			subview[i][j] = runecolor.ColoredRune{Symbol: ' '}
+		}
+	}
+	put := func(line, col int, runes []runecolor.ColoredRune) {
+		if line < len(subview) && col < len(subview[line]) {
+			copy(subview[line][col:], runes)
+		}
+	}
+
+	title := "Art editor  " + e.custom.Name
+	if e.dirty {
+		title += " *"
+	}
+	put(0, 0, colored(title, titleColor))
+
+	// The grid, with dots where nothing is drawn
+	for r, row := range e.cells {
+		for c, cl := range row {
+			cr := runecolor.ColoredRune{Symbol: cl.glyph}
+			if attrs, err := product.ParseHexColor(cl.color); err == nil {
+				cr.ColorAttributes = attrs
+			}
+			if cl.glyph == ' ' {
+				cr = runecolor.ColoredRune{Symbol: '·', ColorAttributes: faintColor}
+			}
+			if r == e.row && c == e.col {
+				cr.ColorAttributes = append(append([]color.Attribute{}, cr.ColorAttributes...), cursorColor...)
+			}
+			put(2+r, c, []runecolor.ColoredRune{cr})
+		}
+	}
+
+	// The crane building it
+	previewArea := make([][]runecolor.ColoredRune, e.preview.Height())
+	for i := range previewArea {
+		previewArea[i] = make([]runecolor.ColoredRune, e.preview.Width())
+	}
+	e.preview.Render(previewArea)
+	for i, line := range previewArea {
+		put(2+i, gridWidth+previewGap, line)
+	}
+
+	bottom := 2 + max(gridHeight, e.preview.Height()) + 1
+	put(bottom, 0, e.paletteLine())
+	switch {
+	case e.message != "" && e.isError:
+		put(bottom+1, 0, colored(e.message, errorColor))
+	case e.message != "":
+		put(bottom+1, 0, colored(e.message, savedColor))
+	}
+	put(bottom+2, 0, colored(help1, faintColor))
+	put(bottom+3, 0, colored(help2, faintColor))
+}
+
+// paletteLine shows a swatch per color with the selected one bracketed.
+func (e *editor) paletteLine() []runecolor.ColoredRune {
+	line := colored("color ", faintColor)
+	for i, hex := range Palette {
+		swatch := runecolor.ColoredRune{Symbol: '█'}
+		if hex == product.NoColor {
+			swatch.Symbol = '□'
+		} else if attrs, err := product.ParseHexColor(hex); err == nil {
+			swatch.ColorAttributes = attrs
+		}
+		left, right := ' ', ' '
+		if i == e.paint {
+			left, right = '[', ']'
+		}
+		line = append(line, runecolor.ColoredRune{Symbol: left}, swatch, runecolor.ColoredRune{Symbol: right})
+	}
+	return line
+}
+
+func colored(text string, attrs []color.Attribute) []runecolor.ColoredRune {
+	return runecolor.ConvertRunesToColoredRunes([]rune(text), nil, attrs)
+}
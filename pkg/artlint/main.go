@@ -0,0 +1,187 @@
+package artlint
+
+import (
+	"fmt"
+	"sort"
+
+	"github.com/anschnapp/pomodorofactory/pkg/audio"
+	"github.com/anschnapp/pomodorofactory/pkg/product"
+	"github.com/anschnapp/pomodorofactory/pkg/runecolor"
+)
+
+// Art larger than this grows the factory canvas for every product.
+const (
+	MaxWidth  = 23
+	MaxHeight = 10
+)
+
+// Severity tells whether a problem breaks the art or only bends the rules.
+type Severity int
+
+const (
+	Warning Severity = iota
+	Error
+)
+
+func (s Severity) String() string {
+	if s == Error {
+		return "error"
+	}
+	return "warning"
+}
+
+// Problem is one finding in a product's art.
+type Problem struct {
+	Product  string
+	Where    string // "art", "frame 2", "art row 4"...
+	Severity Severity
+	Message  string
+}
+
+func (p Problem) String() string {
+	return fmt.Sprintf("%s: %s: %s: %s", p.Product, p.Where, p.Severity, p.Message)
+}
+
+// Check lints the art and animation frames of a product, and its
+// celebration's jingle and sparks.
+func Check(p *product.Product) []Problem {
+	var problems []Problem
+	report := func(where string, severity Severity, format string, args ...any) {
+		problems = append(problems, Problem{p.Name, where, severity, fmt.Sprintf(format, args...)})
+	}
+
+	used := make(map[rune]bool)
+	drawn := make(map[int]bool) // rows with content in the art or any frame
+	width, height := 0, 0
+	pictures := append([][][]runecolor.ColoredRune{p.Art}, p.Frames...)
+	for i, art := range pictures {
+		where := "art"
+		if i > 0 {
+			where = fmt.Sprintf("frame %d", i)
+		}
+		if i > 0 && sameArt(art, p.Art) {
+			continue // the art itself, usually the first frame
+		}
+		rows := trimFinal(art)
+		width = max(width, artWidth(rows))
+		height = max(height, len(rows))
+
+		for r, row := range rows {
+			rowWhere := fmt.Sprintf("%s row %d", where, r+1)
+			for c, cr := range row {
+				switch {
+				case cr.Symbol == '\t':
+					report(rowWhere, Error, "tab in column %d; tabs don't line up on the canvas, use spaces", c+1)
+				case isWide(cr.Symbol):
+					report(rowWhere, Error, "%q in column %d takes two cells and shifts the rest of the row", cr.Symbol, c+1)
+				case isZeroWidth(cr.Symbol):
+					report(rowWhere, Error, "%U in column %d takes no cell and shifts the rest of the row", cr.Symbol, c+1)
+				}
+				if cr.Symbol != ' ' {
+					drawn[r] = true
+					used[cr.Symbol] = true
+				}
+			}
+			if len(row) > 0 && row[len(row)-1].Symbol == ' ' {
+				report(rowWhere, Warning, "trailing spaces widen the row without drawing anything")
+			}
+		}
+	}
+	if width > MaxWidth || height > MaxHeight {
+		report("art", Warning, "%d×%d is larger than %d×%d and grows the canvas for every product", width, height, MaxWidth, MaxHeight)
+	}
+	// A row only a frame draws on, like the coffee's steam, is fine
+	for r := 0; r < height; r++ {
+		if !drawn[r] {
+			report(fmt.Sprintf("art row %d", r+1), Warning, "empty row in the art and every frame; it only takes up space")
+		}
+	}
+
+	var unused []rune
+	for key := range p.ColorMap {
+		if !used[key] {
+			unused = append(unused, key)
+		}
+	}
+	sort.Slice(unused, func(i, j int) bool { return unused[i] < unused[j] })
+	for _, key := range unused {
+		report("color map", Warning, "%q is colored but never drawn", key)
+	}
+
+	if p.Celebration.Jingle != "" {
+		if _, err := audio.ParseJingle(p.Celebration.Jingle); err != nil {
+			report("celebration jingle", Error, "%v", err)
+		}
+	}
+	for _, r := range p.Celebration.Sparks {
+		if isWide(r) || isZeroWidth(r) || r == '\t' {
+			report("celebration sparks", Error, "%q doesn't take exactly one cell", r)
+		}
+	}
+	return problems
+}
+
+func sameArt(a, b [][]runecolor.ColoredRune) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for i := range a {
+		if len(a[i]) != len(b[i]) {
+			return false
+		}
+		for j := range a[i] {
+			if a[i][j].Symbol != b[i][j].Symbol {
+				return false
+			}
+		}
+	}
+	return true
+}
+
+// trimFinal drops the empty row the file's final newline leaves.
+func trimFinal(art [][]runecolor.ColoredRune) [][]runecolor.ColoredRune {
+	if n := len(art); n > 0 && len(art[n-1]) == 0 {
+		return art[:n-1]
+	}
+	return art
+}
+
+func artWidth(art [][]runecolor.ColoredRune) int {
+	width := 0
+	for _, row := range art {
+		width = max(width, len(row))
+	}
+	return width
+}
+
+// isWide reports whether a terminal draws r two cells wide: CJK, Hangul,
+// fullwidth forms and most emoji.
+func isWide(r rune) bool {
+	switch {
+	case r >= 0x1100 && r <= 0x115F,
+		r >= 0x2E80 && r <= 0xA4CF && r != 0x303F,
+		r >= 0xAC00 && r <= 0xD7A3,
+		r >= 0xF900 && r <= 0xFAFF,
+		r >= 0xFE30 && r <= 0xFE4F,
+		r >= 0xFF00 && r <= 0xFF60,
+		r >= 0xFFE0 && r <= 0xFFE6,
+		r >= 0x1F300 && r <= 0x1F64F,
+		r >= 0x1F900 && r <= 0x1F9FF,
+		r >= 0x20000 && r <= 0x3FFFD:
+		return true
+	}
+	return false
+}
+
+// isZeroWidth reports whether r combines with the previous cell or is
+// invisible.
+func isZeroWidth(r rune) bool {
+	switch {
+	case r >= 0x0300 && r <= 0x036F,
+		r >= 0x200B && r <= 0x200F,
+		r >= 0xFE00 && r <= 0xFE0F,
+		r == 0xFEFF:
+		return true
+	}
+	return false
+}
@@ -29,32 +29,47 @@ func charBlipParams(ch rune) (freq float64, dur float64) {
 	return base, 0.06
 }
 
-// GenerateAnimalese generates gibberish speech for a message.
+// GenerateAnimalese generates gibberish speech for a message in the given voice.
+// rng drives the per-character pitch jitter.
 // Returns the PCM buffer and timing data for syncing a visual highlight.
-func GenerateAnimalese(message string) ([]byte, []CharTiming) {
+func GenerateAnimalese(message string, voice Voice, rng *rand.Rand) ([]byte, []CharTiming) {
+	return generateAnimalese(message, voice, rng, func(freq float64, numSamples int) []byte {
+		return synthBlip(freq, numSamples, voice.SawMix)
+	})
+}
+
+// GenerateAnimaleseFromSample speaks a message using a recorded voice sample
+// (e.g. a user's voice.wav) pitched per character instead of the synth blip.
+// The voice's pitch, rate and intonation still apply; its waveform blend does not.
+func GenerateAnimaleseFromSample(message string, sample []byte, voice Voice, rng *rand.Rand) ([]byte, []CharTiming) {
+	return generateAnimalese(message, voice, rng, func(freq float64, numSamples int) []byte {
+		return sampleBlip(sample, freq, numSamples)
+	})
+}
+
+// blipFunc renders one voiced character of numSamples length at freq Hz.
+type blipFunc func(freq float64, numSamples int) []byte
+
+func generateAnimalese(message string, voice Voice, rng *rand.Rand, blip blipFunc) ([]byte, []CharTiming) {
 	var parts [][]byte
 	var timings []CharTiming
 	sampleOffset := 0
-	gapSec := 0.02
+	gapSec := 0.02 / voice.Rate
+	contour := voice.contour([]rune(message))
 
-	for i, ch := range message {
+	for i, ch := range []rune(message) {
 		freq, dur := charBlipParams(ch)
-		// Random pitch variation +/- 15%
-		variation := 1.0 + (rand.Float64()*0.3 - 0.15)
-		freq *= variation
+		dur /= voice.Rate
+		// Random pitch variation, e.g. +/- 15%
+		variation := 1.0 + (rng.Float64()*2-1)*voice.Jitter
+		freq *= variation * voice.Pitch * contour[i]
 
 		numSamples := int(SampleRate * dur)
-		blip := make([]byte, numSamples*bitDepth)
-
+		var voiced []byte
 		if freq > 0 {
-			for s := 0; s < numSamples; s++ {
-				t := float64(s) / float64(numSamples)
-				phase := 2.0 * math.Pi * freq * float64(s) / float64(SampleRate)
-				// Sawtooth + sine mix for retro character
-				saw := 2.0*(math.Mod(float64(s)*freq/float64(SampleRate), 1.0)) - 1.0
-				sample := (0.7*saw + 0.3*math.Sin(phase)) * Envelope(t) * 0.3
-				WriteSample16LE(blip, s, sample)
-			}
+			voiced = blip(freq, numSamples)
+		} else {
+			voiced = make([]byte, numSamples*bitDepth)
 		}
 
 		timings = append(timings, CharTiming{
@@ -62,7 +77,7 @@ func GenerateAnimalese(message string) ([]byte, []CharTiming) {
 			CharIndex:    i,
 			DurationMs:   int(dur * 1000),
 		})
-		parts = append(parts, blip)
+		parts = append(parts, voiced)
 		sampleOffset += numSamples
 
 		gap := MakeSilence(gapSec)
@@ -72,3 +87,42 @@ func GenerateAnimalese(message string) ([]byte, []CharTiming) {
 
 	return ConcatSamples(parts), timings
 }
+
+func synthBlip(freq float64, numSamples int, sawMix float64) []byte {
+	blip := make([]byte, numSamples*bitDepth)
+	for s := 0; s < numSamples; s++ {
+		t := float64(s) / float64(numSamples)
+		phase := 2.0 * math.Pi * freq * float64(s) / float64(SampleRate)
+		// Sawtooth + sine mix for retro character
+		saw := 2.0*(math.Mod(float64(s)*freq/float64(SampleRate), 1.0)) - 1.0
+		sample := (sawMix*saw + (1-sawMix)*math.Sin(phase)) * Envelope(t) * 0.3
+		WriteSample16LE(blip, s, sample)
+	}
+	return blip
+}
+
+// sampleReferenceHz is the pitch a voice sample is assumed to be recorded at.
+// Blips are played back faster or slower relative to it.
+const sampleReferenceHz = 300.0
+
+// sampleBlip plays the start of a voice sample, pitch-shifted by resampling.
+func sampleBlip(voice []byte, freq float64, numSamples int) []byte {
+	blip := make([]byte, numSamples*bitDepth)
+	voiceLen := len(voice) / bitDepth
+	if voiceLen == 0 {
+		return blip
+	}
+	ratio := freq / sampleReferenceHz
+	for s := 0; s < numSamples; s++ {
+		pos := float64(s) * ratio
+		if int(pos) >= voiceLen-1 {
+			break
+		}
+		idx := int(pos)
+		frac := pos - float64(idx)
+		v := ReadSample16LE(voice, idx)*(1-frac) + ReadSample16LE(voice, idx+1)*frac
+		t := float64(s) / float64(numSamples)
+		WriteSample16LE(blip, s, v*Envelope(t))
+	}
+	return blip
+}
@@ -0,0 +1,81 @@
+package audio
+
+import (
+	"fmt"
+	"math/rand"
+	"os"
+	"strings"
+)
+
+// builtinSound describes a synthesized sound that can be rendered by name.
+type builtinSound struct {
+	name      string
+	needsText bool
+	generate  func(text string, rng *rand.Rand) ([]byte, error)
+}
+
+var builtinSounds = []builtinSound{
+	{name: SoundBell, generate: func(string, *rand.Rand) ([]byte, error) { return MakeNotificationSound(), nil }},
+	{name: SoundBreakEnd, generate: func(string, *rand.Rand) ([]byte, error) { return MakeBreakEndSound(), nil }},
+	{name: SoundParty, generate: func(_ string, rng *rand.Rand) ([]byte, error) {
+		pcm, _ := GeneratePartySequence(rng)
+		return pcm, nil
+	}},
+	{name: SoundFanfare, generate: func(string, *rand.Rand) ([]byte, error) { return GenerateFanfare(), nil }},
+	{name: "animalese", needsText: true, generate: func(text string, rng *rand.Rand) ([]byte, error) {
+		pcm, _ := GenerateAnimalese(text, DefaultVoice, rng)
+		return pcm, nil
+	}},
+	{name: "jingle", needsText: true, generate: func(text string, _ *rand.Rand) ([]byte, error) {
+		return RenderJingle(text)
+	}},
+}
+
+// BuiltinNames lists the names accepted by Builtin, in display order.
+func BuiltinNames() []string {
+	names := make([]string, len(builtinSounds))
+	for i, b := range builtinSounds {
+		names[i] = b.name
+	}
+	return names
+}
+
+// BuiltinNeedsText reports whether the named sound is generated from text.
+func BuiltinNeedsText(name string) bool {
+	if _, ok := strings.CutPrefix(name, "animalese:"); ok {
+		return true
+	}
+	for _, b := range builtinSounds {
+		if b.name == name {
+			return b.needsText
+		}
+	}
+	return false
+}
+
+// Builtin renders a built-in sound by name. text is only used by sounds
+// generated from text (see BuiltinNeedsText): the message to speak, or the
+// notation of a jingle.
+// The speech can be given a voice profile as "animalese:<voice>".
+// rng drives the random parts (pops, pitch jitter).
+func Builtin(name string, text string, rng *rand.Rand) ([]byte, error) {
+	if voiceName, ok := strings.CutPrefix(name, "animalese:"); ok {
+		voice, found := VoiceByName(voiceName)
+		if !found {
+			return nil, fmt.Errorf("unknown voice %q", voiceName)
+		}
+		pcm, _ := GenerateAnimalese(text, voice, rng)
+		return pcm, nil
+	}
+	for _, b := range builtinSounds {
+		if b.name == name {
+			return b.generate(text, rng)
+		}
+	}
+	return nil, fmt.Errorf("unknown sound %q", name)
+}
+
+// WriteWAVFile writes PCM samples (signed 16-bit LE, mono, 44100Hz) to path as a WAV file.
+func WriteWAVFile(path string, pcm []byte) error {
+	return os.WriteFile(path, pcmToWAV(pcm), 0o644)
+}
@@ -0,0 +1,327 @@
+package audio
+
+import (
+	"fmt"
+	"math"
+	"math/rand"
+	"strconv"
+	"strings"
+)
+
+// Jingles are written in a small text notation, one token per note or setting:
+//
+//	tempo=200 adsr=8,0,1,15 vol=0.25 gap=30 square:C5/8 E5/8 G5/8 C6/8
+//
+// Settings (key=value) apply to every following note:
+//
+//	tempo=<bpm>              quarter notes per minute (default 120)
+//	adsr=<a>,<d>,<s>,<r>     attack/decay/release in ms, sustain level 0–1
+//	vol=<0–1>                note amplitude (default 0.25)
+//	gap=<ms>                 silence after each note (default 0)
+//
+// Notes are <pitch>[/<length>[.]] with an optional sticky "<wave>:" prefix:
+//
+//	C5 F#4 Bb3               pitch name + octave (A4 = 440Hz)
+//	C5+E5+G5                 chord: pitches played together
+//	rest                     silence
+//	/4 /8 /16 /2.            note length as a fraction of a whole note, "." dots it
+//
+// Waveforms: sine, square, saw, triangle, noise (noise ignores pitch).
+
+// Limits that keep a typo like C5/1e-9 or tempo=0.0001 from rendering an
+// enormous buffer.
+const (
+	maxNoteSeconds   = 4.0  // one note, or the gap after it
+	maxJingleSeconds = 30.0 // the whole jingle
+)
+
+// FanfareJingle is the C5-E5-G5-C6 arpeggio played at the end of the party sequence.
+const FanfareJingle = "tempo=200 adsr=8,0,1,15 vol=0.25 gap=30 square:C5/8 E5/8 G5/8 C6/8"
+
+// BreakEndJingle is the soft two-note chime played when a cooldown ends.
+const BreakEndJingle = "tempo=133 adsr=10,440,0,0 vol=0.4 sine:G5/4 C6/4"
+
+type waveform int
+
+const (
+	waveSine waveform = iota
+	waveSquare
+	waveSaw
+	waveTriangle
+	waveNoise
+)
+
+var waveformNames = map[string]waveform{
+	"sine":     waveSine,
+	"square":   waveSquare,
+	"saw":      waveSaw,
+	"triangle": waveTriangle,
+	"noise":    waveNoise,
+}
+
+type adsr struct {
+	attack, decay, sustain, release float64 // seconds, seconds, level, seconds
+}
+
+type jingleNote struct {
+	freqs    []float64 // empty for a rest
+	duration float64   // seconds, including release
+	gap      float64   // seconds of silence after the note
+	wave     waveform
+	env      adsr
+	volume   float64
+}
+
+// Jingle is a parsed jingle, ready to render.
+type Jingle struct {
+	notes []jingleNote
+}
+
+// ParseJingle parses the jingle notation described above.
+func ParseJingle(src string) (*Jingle, error) {
+	tempo := 120.0
+	env := adsr{attack: 0.005, decay: 0, sustain: 1, release: 0.015}
+	volume := 0.25
+	gap := 0.0
+	wave := waveSine
+	total := 0.0 // seconds so far
+
+	j := &Jingle{}
+	for i, tok := range strings.Fields(src) {
+		if key, value, ok := strings.Cut(tok, "="); ok {
+			var err error
+			switch key {
+			case "tempo":
+				tempo, err = parsePositive(value)
+			case "vol":
+				volume, err = strconv.ParseFloat(value, 64)
+				if err == nil && !(volume >= 0 && volume <= 1) {
+					err = fmt.Errorf("volume must be between 0 and 1")
+				}
+			case "gap":
+				var ms float64
+				ms, err = strconv.ParseFloat(value, 64)
+				if err == nil && !(ms >= 0 && ms <= maxNoteSeconds*1000) {
+					err = fmt.Errorf("gap must be between 0 and %g ms", maxNoteSeconds*1000)
+				}
+				gap = ms / 1000
+			case "adsr":
+				env, err = parseADSR(value)
+			default:
+				err = fmt.Errorf("unknown setting %q", key)
+			}
+			if err != nil {
+				return nil, fmt.Errorf("jingle token %d %q: %w", i+1, tok, err)
+			}
+			continue
+		}
+
+		if name, rest, ok := strings.Cut(tok, ":"); ok {
+			w, known := waveformNames[name]
+			if !known {
+				return nil, fmt.Errorf("jingle token %d %q: unknown waveform %q", i+1, tok, name)
+			}
+			wave = w
+			tok = rest
+		}
+
+		note, err := parseNote(tok, tempo)
+		if err != nil {
+			return nil, fmt.Errorf("jingle token %d %q: %w", i+1, tok, err)
+		}
+		if note.duration > maxNoteSeconds {
+			return nil, fmt.Errorf("jingle token %d %q: notes can last at most %gs", i+1, tok, maxNoteSeconds)
+		}
+		total += note.duration + gap
+		if total > maxJingleSeconds {
+			return nil, fmt.Errorf("jingle token %d %q: jingles can last at most %gs", i+1, tok, maxJingleSeconds)
+		}
+		note.gap = gap
+		note.wave = wave
+		note.env = env
+		note.volume = volume
+		j.notes = append(j.notes, note)
+	}
+	if len(j.notes) == 0 {
+		return nil, fmt.Errorf("jingle has no notes")
+	}
+	return j, nil
+}
+
+// RenderJingle parses and renders a jingle in one step.
+func RenderJingle(src string) ([]byte, error) {
+	j, err := ParseJingle(src)
+	if err != nil {
+		return nil, err
+	}
+	return j.Render(), nil
+}
+
+// mustRenderJingle renders one of the built-in jingles, which are known to parse.
+func mustRenderJingle(src string) []byte {
+	pcm, err := RenderJingle(src)
+	if err != nil {
+		panic(err)
+	}
+	return pcm
+}
+
+// Render synthesizes the jingle as PCM (signed 16-bit LE, mono, 44100Hz).
+func (j *Jingle) Render() []byte {
+	// Fixed seed: a jingle with noise sounds the same every time it plays
+	rng := rand.New(rand.NewSource(1))
+
+	var parts [][]byte
+	for _, n := range j.notes {
+		numSamples := int(SampleRate * n.duration)
+		buf := make([]byte, numSamples*bitDepth)
+		if len(n.freqs) > 0 {
+			for i := 0; i < numSamples; i++ {
+				t := float64(i) / SampleRate
+				sum := 0.0
+				for _, freq := range n.freqs {
+					sum += oscillate(n.wave, freq*t, rng)
+				}
+				sample := sum / float64(len(n.freqs)) * n.env.level(t, n.duration) * n.volume
+				WriteSample16LE(buf, i, sample)
+			}
+		}
+		parts = append(parts, buf)
+		if n.gap > 0 {
+			parts = append(parts, MakeSilence(n.gap))
+		}
+	}
+	return ConcatSamples(parts)
+}
+
+// oscillate returns the waveform value at the given phase (in cycles).
+func oscillate(w waveform, cycles float64, rng *rand.Rand) float64 {
+	frac := cycles - math.Floor(cycles)
+	switch w {
+	case waveSquare:
+		if frac < 0.5 {
+			return 1
+		}
+		return -1
+	case waveSaw:
+		return 2*frac - 1
+	case waveTriangle:
+		return 1 - 4*math.Abs(frac-0.5)
+	case waveNoise:
+		return rng.Float64()*2 - 1
+	default:
+		return math.Sin(2 * math.Pi * frac)
+	}
+}
+
+// level returns the envelope amplitude at time t of a note lasting dur seconds.
+// The release phase fits inside the note so jingles keep their rhythm.
+func (e adsr) level(t, dur float64) float64 {
+	gate := dur - e.release
+	if gate < 0 {
+		gate = 0
+	}
+	held := func(t float64) float64 {
+		switch {
+		case t < e.attack:
+			return t / e.attack
+		case t < e.attack+e.decay:
+			return 1 - (1-e.sustain)*(t-e.attack)/e.decay
+		default:
+			return e.sustain
+		}
+	}
+	if t < gate {
+		return held(t)
+	}
+	if e.release <= 0 {
+		return 0
+	}
+	return held(gate) * math.Max(0, 1-(t-gate)/e.release)
+}
+
+func parseADSR(value string) (adsr, error) {
+	fields := strings.Split(value, ",")
+	if len(fields) != 4 {
+		return adsr{}, fmt.Errorf("adsr needs 4 values: attack,decay,sustain,release")
+	}
+	vals := make([]float64, 4)
+	for i, f := range fields {
+		v, err := strconv.ParseFloat(f, 64)
+		if err != nil || !(v >= 0) || math.IsInf(v, 0) {
+			return adsr{}, fmt.Errorf("invalid adsr value %q", f)
+		}
+		vals[i] = v
+	}
+	if vals[2] > 1 {
+		return adsr{}, fmt.Errorf("sustain level must be between 0 and 1")
+	}
+	return adsr{attack: vals[0] / 1000, decay: vals[1] / 1000, sustain: vals[2], release: vals[3] / 1000}, nil
+}
+
+func parseNote(tok string, tempo float64) (jingleNote, error) {
+	pitches, length, hasLength := strings.Cut(tok, "/")
+
+	// Default to a quarter note
+	beats := 1.0
+	if hasLength {
+		dotted := strings.HasSuffix(length, ".")
+		denom, err := parsePositive(strings.TrimSuffix(length, "."))
+		if err != nil {
+			return jingleNote{}, fmt.Errorf("invalid length %q", length)
+		}
+		beats = 4 / denom
+		if dotted {
+			beats *= 1.5
+		}
+	}
+	note := jingleNote{duration: beats * 60 / tempo}
+
+	if pitches == "rest" {
+		return note, nil
+	}
+	for _, p := range strings.Split(pitches, "+") {
+		freq, err := pitchFrequency(p)
+		if err != nil {
+			return jingleNote{}, err
+		}
+		note.freqs = append(note.freqs, freq)
+	}
+	return note, nil
+}
+
+var pitchSemitones = map[byte]int{'C': 0, 'D': 2, 'E': 4, 'F': 5, 'G': 7, 'A': 9, 'B': 11}
+
+// pitchFrequency converts a pitch name like "C#5" or "Bb3" to Hz (A4 = 440Hz).
+func pitchFrequency(p string) (float64, error) {
+	if len(p) < 2 {
+		return 0, fmt.Errorf("invalid pitch %q", p)
+	}
+	semitone, ok := pitchSemitones[p[0]]
+	if !ok {
+		return 0, fmt.Errorf("invalid pitch %q", p)
+	}
+	rest := p[1:]
+	switch rest[0] {
+	case '#':
+		semitone++
+		rest = rest[1:]
+	case 'b':
+		semitone--
+		rest = rest[1:]
+	}
+	octave, err := strconv.Atoi(rest)
+	if err != nil || octave < 0 || octave > 9 {
+		return 0, fmt.Errorf("invalid octave in pitch %q", p)
+	}
+	midi := (octave+1)*12 + semitone
+	return 440 * math.Pow(2, float64(midi-69)/12), nil
+}
+
+func parsePositive(value string) (float64, error) {
+	v, err := strconv.ParseFloat(value, 64)
+	if err != nil || !(v > 0) || math.IsInf(v, 0) {
+		return 0, fmt.Errorf("expected a positive number, got %q", value)
+	}
+	return v, nil
+}
@@ -34,3 +34,9 @@ func MakeNotificationSound() []byte {
 	}
 	return buf
 }
+
+// MakeBreakEndSound generates a soft two-note chime (G5 then C6) signalling
+// that the cooldown is over — friendlier than the work-done bell.
+func MakeBreakEndSound() []byte {
+	return mustRenderJingle(BreakEndJingle)
+}
@@ -20,12 +20,12 @@ func GenerateRisingTone(startHz, endHz, durationSec float64) []byte {
 }
 
 // GeneratePop creates a short noise burst with exponential decay.
-func GeneratePop(durationSec float64) []byte {
+func GeneratePop(durationSec float64, rng *rand.Rand) []byte {
 	numSamples := int(SampleRate * durationSec)
 	buf := make([]byte, numSamples*bitDepth)
 	for i := 0; i < numSamples; i++ {
 		t := float64(i) / float64(numSamples)
-		noise := rand.Float64()*2.0 - 1.0
+		noise := rng.Float64()*2.0 - 1.0
 		decay := math.Exp(-8.0 * t)
 		sample := noise * decay * 0.4
 		WriteSample16LE(buf, i, sample)
@@ -35,29 +35,18 @@ func GeneratePop(durationSec float64) []byte {
 
 // GenerateFanfare creates a major chord arpeggio (C5-E5-G5-C6) with square waves.
 func GenerateFanfare() []byte {
-	notes := []float64{523.25, 659.25, 783.99, 1046.50}
-	noteDur := 0.15
-	gap := 0.03
-
-	var parts [][]byte
-	for _, freq := range notes {
-		numSamples := int(SampleRate * noteDur)
-		note := make([]byte, numSamples*bitDepth)
-		for i := 0; i < numSamples; i++ {
-			t := float64(i) / float64(numSamples)
-			phase := 2.0 * math.Pi * freq * float64(i) / float64(SampleRate)
-			sample := math.Copysign(0.25, math.Sin(phase)) * Envelope(t)
-			WriteSample16LE(note, i, sample)
-		}
-		parts = append(parts, note)
-		parts = append(parts, MakeSilence(gap))
-	}
-	return ConcatSamples(parts)
+	return mustRenderJingle(FanfareJingle)
 }
 
 // GeneratePartySequence builds the full party sound: rising tones + pops + fanfare.
 // Returns the PCM buffer and its duration in seconds.
-func GeneratePartySequence() ([]byte, float64) {
+func GeneratePartySequence(rng *rand.Rand) ([]byte, float64) {
+	return partySequenceWithFanfare(GenerateFanfare(), rng)
+}
+
+// partySequenceWithFanfare builds the party sound around a given fanfare,
+// so a sound pack can swap just the fanfare and keep the rising tones and pops.
+func partySequenceWithFanfare(fanfare []byte, rng *rand.Rand) ([]byte, float64) {
 	var parts [][]byte
 
 	// Three quick rising tones
@@ -70,12 +59,12 @@ func GeneratePartySequence() ([]byte, float64) {
 
 	// Two pops
 	for i := 0; i < 2; i++ {
-		parts = append(parts, GeneratePop(0.1))
+		parts = append(parts, GeneratePop(0.1, rng))
 		parts = append(parts, MakeSilence(0.05))
 	}
 
 	// Fanfare
-	parts = append(parts, GenerateFanfare())
+	parts = append(parts, fanfare)
 
 	combined := ConcatSamples(parts)
 	return combined, DurationSec(combined)
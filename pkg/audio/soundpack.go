@@ -0,0 +1,125 @@
+package audio
+
+import (
+	"errors"
+	"fmt"
+	"math/rand"
+	"os"
+	"path/filepath"
+)
+
+// Sound names a replaceable sound. A sound pack provides it as <name>.wav,
+// or as <name>.jingle written in the jingle notation (see ParseJingle).
+const (
+	SoundBell     = "bell"      // work-done alarm
+	SoundParty    = "party"     // the whole celebration party sequence
+	SoundFanfare  = "fanfare"   // only the fanfare at the end of the party sequence
+	SoundBreakEnd = "break_end" // cooldown finished
+	SoundVoice    = "voice"     // sample pitched per character for the speech
+)
+
+var packSounds = []string{SoundBell, SoundParty, SoundFanfare, SoundBreakEnd, SoundVoice}
+
+// SoundPack holds user-supplied sounds that replace the synthesized ones.
+// Anything missing from the pack — or a nil *SoundPack — falls back to the
+// built-in sound, so every method is always safe to call.
+type SoundPack struct {
+	sounds map[string][]byte // decoded PCM keyed by sound name
+}
+
+// packDecoders maps the file extensions a pack may use to their decoders,
+// in lookup order.
+var packDecoders = []struct {
+	ext    string
+	decode func([]byte) ([]byte, error)
+}{
+	{ext: ".wav", decode: DecodeWAV},
+	{ext: ".jingle", decode: func(data []byte) ([]byte, error) { return RenderJingle(string(data)) }},
+}
+
+// LoadSoundPack reads every known sound from dir. A missing directory or
+// file is not an error. Files that fail to decode are skipped and reported
+// in the returned error; the pack is still usable.
+func LoadSoundPack(dir string) (*SoundPack, error) {
+	p := &SoundPack{sounds: make(map[string][]byte)}
+	var errs []error
+	for _, name := range packSounds {
+		for _, d := range packDecoders {
+			path := filepath.Join(dir, name+d.ext)
+			data, err := os.ReadFile(path)
+			if err != nil {
+				if !errors.Is(err, os.ErrNotExist) {
+					errs = append(errs, err)
+				}
+				continue
+			}
+			pcm, err := d.decode(data)
+			if err != nil {
+				errs = append(errs, fmt.Errorf("%s: %w", path, err))
+				continue
+			}
+			p.sounds[name] = pcm
+			break
+		}
+	}
+	return p, errors.Join(errs...)
+}
+
+func (p *SoundPack) lookup(name string) ([]byte, bool) {
+	if p == nil {
+		return nil, false
+	}
+	pcm, ok := p.sounds[name]
+	return pcm, ok
+}
+
+// Bell returns the sound played when a pomodoro finishes.
+func (p *SoundPack) Bell() []byte {
+	if pcm, ok := p.lookup(SoundBell); ok {
+		return pcm
+	}
+	return MakeNotificationSound()
+}
+
+// BreakEnd returns the sound played when a cooldown finishes.
+func (p *SoundPack) BreakEnd() []byte {
+	if pcm, ok := p.lookup(SoundBreakEnd); ok {
+		return pcm
+	}
+	return MakeBreakEndSound()
+}
+
+// Party returns the celebration party sound and its duration in seconds.
+// A pack's party.wav replaces the whole sequence; a fanfare.wav only
+// replaces the closing fanfare.
+func (p *SoundPack) Party(rng *rand.Rand) ([]byte, float64) {
+	if pcm, ok := p.lookup(SoundParty); ok {
+		return pcm, DurationSec(pcm)
+	}
+	if fanfare, ok := p.lookup(SoundFanfare); ok {
+		return partySequenceWithFanfare(fanfare, rng)
+	}
+	return GeneratePartySequence(rng)
+}
+
+// PartyWithFanfare is Party with a product's own fanfare in place of the
+// pack's or the built-in one; a pack's party.wav still wins. A nil fanfare
+// is the same as Party.
+func (p *SoundPack) PartyWithFanfare(fanfare []byte, rng *rand.Rand) ([]byte, float64) {
+	if pcm, ok := p.lookup(SoundParty); ok {
+		return pcm, DurationSec(pcm)
+	}
+	if fanfare == nil {
+		return p.Party(rng)
+	}
+	return partySequenceWithFanfare(fanfare, rng)
+}
+
+// Speech returns the gibberish speech for a message in the given voice,
+// built from the pack's voice.wav if present.
+func (p *SoundPack) Speech(message string, voice Voice, rng *rand.Rand) ([]byte, []CharTiming) {
+	if sample, ok := p.lookup(SoundVoice); ok {
+		return GenerateAnimaleseFromSample(message, sample, voice, rng)
+	}
+	return GenerateAnimalese(message, voice, rng)
+}
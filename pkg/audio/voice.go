@@ -0,0 +1,63 @@
+package audio
+
+import "strings"
+
+// Voice shapes the gibberish speech: how high, how fast, how buzzy and how
+// sing-song it sounds.
+type Voice struct {
+	Name       string
+	Pitch      float64 // multiplier on the per-character blip frequencies
+	Rate       float64 // speaking speed multiplier (2 = twice as fast)
+	SawMix     float64 // waveform blend: 0 = pure sine, 1 = pure sawtooth
+	Jitter     float64 // random pitch variation per character (0.15 = ±15%)
+	Intonation float64 // pitch rise toward "!"/"?" and fall at sentence end (0 = flat)
+}
+
+// DefaultVoice is the original factory announcer.
+var DefaultVoice = Voice{Name: "announcer", Pitch: 1.0, Rate: 1.0, SawMix: 0.7, Jitter: 0.15, Intonation: 0.2}
+
+// Voices lists the selectable voice profiles.
+var Voices = []Voice{
+	DefaultVoice,
+	{Name: "foreman", Pitch: 0.55, Rate: 0.8, SawMix: 0.9, Jitter: 0.08, Intonation: 0.15},
+	{Name: "robot", Pitch: 1.6, Rate: 1.3, SawMix: 1.0, Jitter: 0, Intonation: 0},
+	{Name: "intern", Pitch: 1.4, Rate: 1.25, SawMix: 0.35, Jitter: 0.25, Intonation: 0.4},
+}
+
+// VoiceByName looks up a voice profile. Unknown names return DefaultVoice and false.
+func VoiceByName(name string) (Voice, bool) {
+	for _, v := range Voices {
+		if strings.EqualFold(v.Name, name) {
+			return v, true
+		}
+	}
+	return DefaultVoice, false
+}
+
+// contour returns a pitch multiplier per rune: each sentence rises toward a
+// closing "!" or "?" and falls toward a "." or the end of the message.
+func (v Voice) contour(runes []rune) []float64 {
+	mult := make([]float64, len(runes))
+	start := 0
+	for i := range runes {
+		end := i == len(runes)-1
+		if !end && !strings.ContainsRune(".!?", runes[i]) {
+			continue
+		}
+		rising := runes[i] == '!' || runes[i] == '?'
+		span := i - start
+		for j := start; j <= i; j++ {
+			pos := 1.0
+			if span > 0 {
+				pos = float64(j-start) / float64(span)
+			}
+			if rising {
+				mult[j] = 1 + v.Intonation*pos*pos
+			} else {
+				mult[j] = 1 - 0.5*v.Intonation*pos*pos
+			}
+		}
+		start = i + 1
+	}
+	return mult
+}
@@ -0,0 +1,186 @@
+package audio
+
+import (
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"math"
+)
+
+const (
+	wavFormatPCM        = 1
+	wavFormatFloat      = 3
+	wavFormatExtensible = 0xFFFE
+)
+
+// Sample rates DecodeWAV accepts; anything outside is a corrupt header
+// and would resample into a useless or enormous buffer.
+const (
+	minWAVRate = 4000
+	maxWAVRate = 192000
+)
+
+type wavFormat struct {
+	format        uint16
+	channels      int
+	sampleRate    int
+	bitsPerSample int
+}
+
+// DecodeWAV parses a RIFF/WAVE file and converts it to the engine's native
+// format (signed 16-bit LE, mono, 44100Hz). Integer PCM (8/16/24/32-bit) and
+// IEEE float (32/64-bit) are supported; multi-channel audio is downmixed and
+// other sample rates are linearly resampled.
+func DecodeWAV(data []byte) ([]byte, error) {
+	if len(data) < 12 || string(data[0:4]) != "RIFF" || string(data[8:12]) != "WAVE" {
+		return nil, errors.New("not a RIFF/WAVE file")
+	}
+
+	var fmtChunk *wavFormat
+	var pcm []byte
+	pos := 12
+	for pos+8 <= len(data) {
+		id := string(data[pos : pos+4])
+		size := int(binary.LittleEndian.Uint32(data[pos+4 : pos+8]))
+		body := data[pos+8:]
+		if size > len(body) {
+			size = len(body) // tolerate truncated files (common when streamed)
+		}
+		body = body[:size]
+
+		switch id {
+		case "fmt ":
+			f, err := parseWAVFormat(body)
+			if err != nil {
+				return nil, err
+			}
+			fmtChunk = f
+		case "data":
+			pcm = body
+		}
+
+		// Chunks are padded to an even size
+		pos += 8 + size + size%2
+	}
+
+	if fmtChunk == nil {
+		return nil, errors.New("missing fmt chunk")
+	}
+	if pcm == nil {
+		return nil, errors.New("missing data chunk")
+	}
+
+	mono := decodeFrames(pcm, fmtChunk)
+	mono = resampleLinear(mono, fmtChunk.sampleRate, SampleRate)
+
+	out := make([]byte, len(mono)*bitDepth)
+	for i, s := range mono {
+		WriteSample16LE(out, i, s)
+	}
+	return out, nil
+}
+
+func parseWAVFormat(body []byte) (*wavFormat, error) {
+	if len(body) < 16 {
+		return nil, errors.New("fmt chunk too short")
+	}
+	f := &wavFormat{
+		format:        binary.LittleEndian.Uint16(body[0:2]),
+		channels:      int(binary.LittleEndian.Uint16(body[2:4])),
+		sampleRate:    int(binary.LittleEndian.Uint32(body[4:8])),
+		bitsPerSample: int(binary.LittleEndian.Uint16(body[14:16])),
+	}
+	if f.format == wavFormatExtensible {
+		// WAVE_FORMAT_EXTENSIBLE: the real format tag is the first two bytes of the sub-format GUID
+		if len(body) < 26 {
+			return nil, errors.New("extensible fmt chunk too short")
+		}
+		f.format = binary.LittleEndian.Uint16(body[24:26])
+	}
+	if f.channels < 1 {
+		return nil, errors.New("wav has no channels")
+	}
+	if f.sampleRate < minWAVRate || f.sampleRate > maxWAVRate {
+		return nil, fmt.Errorf("unsupported wav sample rate %dHz (expected %d to %dHz)", f.sampleRate, minWAVRate, maxWAVRate)
+	}
+
+	switch {
+	case f.format == wavFormatPCM && (f.bitsPerSample == 8 || f.bitsPerSample == 16 || f.bitsPerSample == 24 || f.bitsPerSample == 32):
+	case f.format == wavFormatFloat && (f.bitsPerSample == 32 || f.bitsPerSample == 64):
+	default:
+		return nil, fmt.Errorf("unsupported wav encoding (format %d, %d-bit)", f.format, f.bitsPerSample)
+	}
+	return f, nil
+}
+
+// decodeFrames converts interleaved samples to mono floats in [-1.0, 1.0].
+func decodeFrames(pcm []byte, f *wavFormat) []float64 {
+	sampleBytes := f.bitsPerSample / 8
+	frameBytes := sampleBytes * f.channels
+	numFrames := len(pcm) / frameBytes
+
+	mono := make([]float64, numFrames)
+	for i := 0; i < numFrames; i++ {
+		sum := 0.0
+		for ch := 0; ch < f.channels; ch++ {
+			off := i*frameBytes + ch*sampleBytes
+			sum += decodeSample(pcm[off:off+sampleBytes], f.format)
+		}
+		mono[i] = sum / float64(f.channels)
+	}
+	return mono
+}
+
+func decodeSample(b []byte, format uint16) float64 {
+	if format == wavFormatFloat {
+		if len(b) == 8 {
+			return math.Float64frombits(binary.LittleEndian.Uint64(b))
+		}
+		return float64(math.Float32frombits(binary.LittleEndian.Uint32(b)))
+	}
+	switch len(b) {
+	case 1:
+		// 8-bit WAV is unsigned
+		return (float64(b[0]) - 128.0) / 128.0
+	case 2:
+		return float64(int16(binary.LittleEndian.Uint16(b))) / 32768.0
+	case 3:
+		v := int32(b[0]) | int32(b[1])<<8 | int32(int8(b[2]))<<16
+		return float64(v) / 8388608.0
+	default:
+		return float64(int32(binary.LittleEndian.Uint32(b))) / 2147483648.0
+	}
+}
+
+// resampleLinear converts samples from one rate to another by linear interpolation.
+func resampleLinear(in []float64, fromRate, toRate int) []float64 {
+	if fromRate == toRate || len(in) == 0 {
+		return in
+	}
+	outLen := int(float64(len(in)) * float64(toRate) / float64(fromRate))
+	out := make([]float64, outLen)
+	step := float64(fromRate) / float64(toRate)
+	for i := range out {
+		out[i] = interpolate(in, float64(i)*step)
+	}
+	return out
+}
+
+// interpolate reads in at a fractional index, clamping at the end of the buffer.
+func interpolate(in []float64, pos float64) float64 {
+	idx := int(pos)
+	if idx >= len(in)-1 {
+		return in[len(in)-1]
+	}
+	frac := pos - float64(idx)
+	return in[idx]*(1-frac) + in[idx+1]*frac
+}
+
+// ReadSample16LE reads the signed 16-bit LE sample at idx as a float64 in [-1.0, 1.0].
+func ReadSample16LE(buf []byte, idx int) float64 {
+	off := idx * bitDepth
+	if off+1 >= len(buf) {
+		return 0
+	}
+	return float64(int16(uint16(buf[off])|uint16(buf[off+1])<<8)) / 32768.0
+}
@@ -1,6 +1,7 @@
 package celebration
 
 import (
+	"math/rand"
 	"time"
 
 	"github.com/anschnapp/pomodorofactory/pkg/audio"
@@ -20,8 +21,11 @@ const (
 type Celebration struct {
 	phase     Phase
 	startTime time.Time
-	engine    *audio.Engine // nil if audio unavailable
-	message   string        // the congratulatory message for this run
+	engine    *audio.Engine    // nil if audio unavailable
+	sounds    *audio.SoundPack // user sound overrides; nil means built-in sounds
+	message   string           // the congratulatory message for this run
+	voice     audio.Voice      // who speaks the message
+	rng       *rand.Rand       // drives pops and pitch jitter
 
 	// Party phase
 	partyDuration time.Duration
@@ -34,11 +38,14 @@ type Celebration struct {
 	speechDone  <-chan struct{}
 }
 
-// New creates a celebration coordinator. engine may be nil for visual-only mode.
-func New(engine *audio.Engine) *Celebration {
+// New creates a celebration coordinator. engine may be nil for visual-only mode,
+// sounds may be nil to use only the built-in sounds.
+func New(engine *audio.Engine, sounds *audio.SoundPack, rng *rand.Rand) *Celebration {
 	return &Celebration{
 		phase:  PhaseNone,
 		engine: engine,
+		sounds: sounds,
+		rng:    rng,
 	}
 }
 
@@ -46,19 +53,37 @@ func New(engine *audio.Engine) *Celebration {
 func (c *Celebration) Message() string { return c.message }
 
 // Start kicks off the party phase. Call once when the timer finishes.
-// message is the text that will be spoken in the speech phase.
-func (c *Celebration) Start(message string) {
+// message is the text that will be spoken in the speech phase, in the given voice.
+// fanfare closes the party in place of the default one; nil keeps the default.
+func (c *Celebration) Start(message string, voice audio.Voice, fanfare []byte) {
+	c.start(message, voice, fanfare, 1)
+}
+
+// StartGrand is Start with the party played twice over, for finishing a
+// mega-build.
+func (c *Celebration) StartGrand(message string, voice audio.Voice, fanfare []byte) {
+	c.start(message, voice, fanfare, 2)
+}
+
+func (c *Celebration) start(message string, voice audio.Voice, fanfare []byte, rounds int) {
 	c.message = message
+	c.voice = voice
 	c.phase = PhaseParty
 	c.startTime = time.Now()
 	c.partyTick = 0
 
 	if c.engine != nil {
-		samples, dur := audio.GeneratePartySequence()
+		var parts [][]byte
+		var dur float64
+		for i := 0; i < rounds; i++ {
+			samples, d := c.sounds.PartyWithFanfare(fanfare, c.rng)
+			parts = append(parts, samples)
+			dur += d
+		}
 		c.partyDuration = time.Duration(dur * float64(time.Second))
-		c.engine.Play(samples)
+		c.engine.Play(audio.ConcatSamples(parts))
 	} else {
-		c.partyDuration = 3 * time.Second
+		c.partyDuration = time.Duration(rounds) * 3 * time.Second
 	}
 }
 
@@ -99,7 +124,7 @@ func (c *Celebration) startSpeechPhase() {
 
 	msg := c.message
 	if c.engine != nil {
-		samples, timings := audio.GenerateAnimalese(msg)
+		samples, timings := c.sounds.Speech(msg, c.voice, c.rng)
 		c.charTimings = timings
 		c.speechDone = c.engine.Play(samples)
 	} else {
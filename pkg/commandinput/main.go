@@ -3,22 +3,49 @@ package commandinput
 import (
 	"strings"
 
+	"github.com/anschnapp/pomodorofactory/pkg/input"
 	"github.com/anschnapp/pomodorofactory/pkg/runecolor"
 	"github.com/anschnapp/pomodorofactory/pkg/slicehelper"
+	"github.com/fatih/color"
 )
 
-const cmdWidth = 50
+// BarWidth is how many columns the command bar has.
+const BarWidth = 50
+
+// Separator goes between the items of the command row.
+const Separator = " | "
+
+// Item is one entry of the command bar. Items with an Action are clickable.
+type Item struct {
+	Text   string
+	Action string
+}
+
+// hitRange is the span of columns [start, end) on a row occupied by an action.
+type hitRange struct {
+	row        int
+	start, end int
+	action     string
+}
 
 type commandinput struct {
 	width              int
 	height             int
 	asciRepresentation [][]runecolor.ColoredRune
+	hits               []hitRange
+
+	// The ":" command line takes over the selector row while open
+	prompt    prompt
+	prompting bool
+	completer Completer
+	message   string // feedback for the last command, shown until the next key
+	isError   bool
 }
 
 func MakeCommandinput() *commandinput {
 	c := &commandinput{
 		height: 4,
-		width:  cmdWidth,
+		width:  BarWidth,
 	}
 	c.SetTexts("[s]tart | [q]uit", "")
 	return c
@@ -27,6 +54,17 @@ func MakeCommandinput() *commandinput {
 // SetTexts updates the command bar with a command line and an optional selector line.
 // Both lines are padded/truncated to fit the fixed width.
 func (c *commandinput) SetTexts(commandText, selectorText string) {
+	c.SetItems([]Item{{Text: commandText}}, []Item{{Text: selectorText}})
+}
+
+// SetItems is SetTexts with clickable entries: commands are joined with
+// " | ", selector items are concatenated as-is. The column range of every
+// item with an Action is remembered for ActionAt.
+func (c *commandinput) SetItems(commands []Item, selector []Item) {
+	c.hits = nil
+	commandText := c.layoutRow(1, commands, Separator)
+	selectorText := c.layoutRow(2, selector, "")
+
 	sep := strings.Repeat("-", c.width)
 	asci := make([][]runecolor.ColoredRune, 4)
 	asci[0] = runecolor.ConvertSimpleRunes([]rune(sep))
@@ -36,6 +74,98 @@ func (c *commandinput) SetTexts(commandText, selectorText string) {
 	c.asciRepresentation = asci
 }
 
+// layoutRow joins items into one line and records where each action lands.
+func (c *commandinput) layoutRow(row int, items []Item, separator string) string {
+	var sb strings.Builder
+	col := 0
+	for i, item := range items {
+		if i > 0 {
+			sb.WriteString(separator)
+			col += len([]rune(separator))
+		}
+		width := len([]rune(item.Text))
+		if item.Action != "" {
+			c.hits = append(c.hits, hitRange{row: row, start: col, end: col + width, action: item.Action})
+		}
+		sb.WriteString(item.Text)
+		col += width
+	}
+	return sb.String()
+}
+
+// ActionAt returns the action rendered at a row/column of the component.
+func (c *commandinput) ActionAt(row, col int) (string, bool) {
+	if row == promptRow && (c.prompting || c.message != "") {
+		return "", false
+	}
+	for _, h := range c.hits {
+		if h.row == row && col >= h.start && col < h.end && col < c.width {
+			return h.action, true
+		}
+	}
+	return "", false
+}
+
+// promptRow is the row the command line and command feedback are shown on.
+const promptRow = 2
+
+// SetCompleter sets where tab completion gets its candidates from.
+func (c *commandinput) SetCompleter(complete Completer) {
+	c.completer = complete
+}
+
+// OpenPrompt shows an empty ":" command line.
+func (c *commandinput) OpenPrompt() {
+	c.prompting = true
+	c.message = ""
+	c.prompt.reset()
+}
+
+// Prompting reports whether the command line is open and wants all keys.
+func (c *commandinput) Prompting() bool {
+	return c.prompting
+}
+
+// HandleKey edits the command line. When the line is submitted with Enter
+// it returns the entered command and true; the prompt closes on Enter,
+// Esc or when the line is backspaced away.
+func (c *commandinput) HandleKey(ev input.KeyEvent) (string, bool) {
+	switch c.prompt.handleKey(ev, c.completer) {
+	case promptSubmitted:
+		c.prompting = false
+		return c.prompt.submit(), true
+	case promptCancelled:
+		c.prompting = false
+		c.prompt.reset()
+	}
+	return "", false
+}
+
+// SetMessage shows feedback for a command in place of the selector row.
+// It stays until ClearMessage is called or the prompt opens again.
+func (c *commandinput) SetMessage(text string, isError bool) {
+	c.message = text
+	c.isError = isError
+}
+
+func (c *commandinput) ClearMessage() {
+	c.message = ""
+}
+
+// promptLine renders ":" and the command line, scrolled so the cursor
+// (shown in reverse video) stays visible.
+func (c *commandinput) promptLine() []runecolor.ColoredRune {
+	text := append([]rune{':'}, c.prompt.line...)
+	cursor := c.prompt.cursor + 1
+	offset := 0
+	if cursor >= c.width {
+		offset = cursor - c.width + 1
+	}
+	row := runecolor.ConvertSimpleRunes(padToWidth(string(text[offset:]), c.width))
+	row[cursor-offset].ColorAttributes = runecolor.MakeSingleColorAttributes(color.ReverseVideo)
+	return row
+}
+
 func padToWidth(s string, width int) []rune {
 	runes := []rune(s)
 	if len(runes) >= width {
@@ -64,4 +194,15 @@ func (c *commandinput) Render(subview [][]runecolor.ColoredRune) {
 		}
 	}
 	slicehelper.Copy2DSlice(c.asciRepresentation, subview)
+
+	switch {
+	case c.prompting:
+		copy(subview[promptRow], c.promptLine())
+	case c.message != "":
+		attrs := runecolor.MakeSingleColorAttributes(color.FgHiGreen)
+		if c.isError {
+			attrs = runecolor.MakeSingleColorAttributes(color.FgHiRed)
+		}
+		copy(subview[promptRow], runecolor.ConvertRunesToColoredRunes(padToWidth(c.message, c.width), nil, attrs))
+	}
 }
@@ -0,0 +1,204 @@
+package commandinput
+
+import (
+	"strings"
+	"unicode"
+
+	"github.com/anschnapp/pomodorofactory/pkg/input"
+)
+
+// Completer returns the candidates for the last word of a command line.
+// words holds the words before it, partial is what has been typed of it.
+type Completer func(words []string, partial string) []string
+
+// prompt is the ":" command line: a single line editor with history and
+// tab completion.
+type prompt struct {
+	line    []rune
+	cursor  int
+	history []string
+	histIdx int    // len(history) while editing a fresh line
+	draft   string // the fresh line, kept while browsing history
+
+	// Tab cycling through completions of the word starting at compStart
+	completions []string
+	compIdx     int
+	compStart   int
+}
+
+func (p *prompt) reset() {
+	p.line = nil
+	p.cursor = 0
+	p.histIdx = len(p.history)
+	p.draft = ""
+	p.completions = nil
+}
+
+func (p *prompt) String() string {
+	return string(p.line)
+}
+
+// submit records the line in history (skipping blanks and repeats).
+func (p *prompt) submit() string {
+	line := strings.TrimSpace(string(p.line))
+	if line != "" && (len(p.history) == 0 || p.history[len(p.history)-1] != line) {
+		p.history = append(p.history, line)
+	}
+	p.reset()
+	return line
+}
+
+// promptResult tells the caller what a key did to the prompt.
+type promptResult int
+
+const (
+	promptEditing promptResult = iota
+	promptSubmitted
+	promptCancelled
+)
+
+func (p *prompt) handleKey(ev input.KeyEvent, complete Completer) promptResult {
+	if ev.Key != input.KeyTab {
+		p.completions = nil
+	}
+	ctrl := ev.Mod&input.ModCtrl != 0
+
+	switch {
+	case ev.Key == input.KeyEnter:
+		return promptSubmitted
+	case ev.Key == input.KeyEscape, ctrl && (ev.Rune == 'c' || ev.Rune == 'g'):
+		return promptCancelled
+	case ev.Key == input.KeyBackspace:
+		if len(p.line) == 0 {
+			return promptCancelled // like vim, backspacing over ":" leaves
+		}
+		if p.cursor > 0 {
+			p.delete(p.cursor-1, p.cursor)
+		}
+	case ev.Key == input.KeyDelete, ctrl && ev.Rune == 'd':
+		if p.cursor < len(p.line) {
+			p.delete(p.cursor, p.cursor+1)
+		}
+	case ev.Key == input.KeyLeft, ctrl && ev.Rune == 'b':
+		if p.cursor > 0 {
+			p.cursor--
+		}
+	case ev.Key == input.KeyRight, ctrl && ev.Rune == 'f':
+		if p.cursor < len(p.line) {
+			p.cursor++
+		}
+	case ev.Key == input.KeyHome, ctrl && ev.Rune == 'a':
+		p.cursor = 0
+	case ev.Key == input.KeyEnd, ctrl && ev.Rune == 'e':
+		p.cursor = len(p.line)
+	case ctrl && ev.Rune == 'u':
+		p.delete(0, p.cursor)
+	case ctrl && ev.Rune == 'k':
+		p.delete(p.cursor, len(p.line))
+	case ctrl && ev.Rune == 'w':
+		p.delete(p.wordStart(), p.cursor)
+	case ev.Key == input.KeyUp, ctrl && ev.Rune == 'p':
+		p.browseHistory(-1)
+	case ev.Key == input.KeyDown, ctrl && ev.Rune == 'n':
+		p.browseHistory(1)
+	case ev.Key == input.KeyTab:
+		p.complete(complete, ev.Mod&input.ModShift != 0)
+	case ev.Key == input.KeyPaste:
+		p.insert(strings.Map(func(r rune) rune {
+			if unicode.IsControl(r) {
+				return ' '
+			}
+			return r
+		}, ev.Paste))
+	case ev.Key == input.KeyRune && ev.Mod&(input.ModCtrl|input.ModAlt) == 0:
+		p.insert(string(ev.Rune))
+	}
+	return promptEditing
+}
+
+func (p *prompt) insert(s string) {
+	runes := []rune(s)
+	line := make([]rune, 0, len(p.line)+len(runes))
+	line = append(line, p.line[:p.cursor]...)
+	line = append(line, runes...)
+	line = append(line, p.line[p.cursor:]...)
+	p.line = line
+	p.cursor += len(runes)
+}
+
+func (p *prompt) delete(from, to int) {
+	p.line = append(p.line[:from], p.line[to:]...)
+	p.cursor = from
+}
+
+// wordStart is the start of the word left of the cursor (Ctrl+W semantics).
+func (p *prompt) wordStart() int {
+	i := p.cursor
+	for i > 0 && p.line[i-1] == ' ' {
+		i--
+	}
+	for i > 0 && p.line[i-1] != ' ' {
+		i--
+	}
+	return i
+}
+
+func (p *prompt) browseHistory(dir int) {
+	idx := p.histIdx + dir
+	if idx < 0 || idx > len(p.history) {
+		return
+	}
+	if p.histIdx == len(p.history) {
+		p.draft = string(p.line)
+	}
+	p.histIdx = idx
+	if idx == len(p.history) {
+		p.line = []rune(p.draft)
+	} else {
+		p.line = []rune(p.history[idx])
+	}
+	p.cursor = len(p.line)
+}
+
+// complete replaces the word under the cursor with the next (or, with
+// backwards, previous) candidate. A single candidate also gets a trailing
+// space so the next argument can be typed right away.
+func (p *prompt) complete(complete Completer, backwards bool) {
+	if complete == nil {
+		return
+	}
+	if p.completions == nil {
+		p.compStart = p.cursor
+		for p.compStart > 0 && p.line[p.compStart-1] != ' ' {
+			p.compStart--
+		}
+		words := strings.Fields(string(p.line[:p.compStart]))
+		partial := string(p.line[p.compStart:p.cursor])
+		var matches []string
+		for _, c := range complete(words, partial) {
+			if strings.HasPrefix(c, partial) {
+				matches = append(matches, c)
+			}
+		}
+		if len(matches) == 0 {
+			return
+		}
+		if len(matches) == 1 {
+			p.delete(p.compStart, p.cursor)
+			p.insert(matches[0] + " ")
+			return
+		}
+		p.completions = matches
+		p.compIdx = -1
+		if backwards {
+			p.compIdx = 0
+		}
+	}
+	if backwards {
+		p.compIdx = (p.compIdx - 1 + len(p.completions)) % len(p.completions)
+	} else {
+		p.compIdx = (p.compIdx + 1) % len(p.completions)
+	}
+	p.delete(p.compStart, p.cursor)
+	p.insert(p.completions[p.compIdx])
+}
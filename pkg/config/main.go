@@ -0,0 +1,59 @@
+package config
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"os"
+	"strings"
+)
+
+// Setting is one "name = value" line of a config file.
+type Setting struct {
+	Name  string
+	Value string
+	Line  int
+}
+
+// Load reads a config file such as keymap.conf or schedules.conf. A
+// missing file yields no settings.
+//
+// One setting per line:
+//
+//	# comments and blank lines are ignored
+//	sprint = work 15, break 3
+func Load(path string) ([]Setting, error) {
+	f, err := os.Open(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	var settings []Setting
+	scanner := bufio.NewScanner(f)
+	lineNo := 0
+	for scanner.Scan() {
+		lineNo++
+		line := scanner.Text()
+		if i := strings.Index(line, "#"); i >= 0 {
+			line = line[:i]
+		}
+		line = strings.TrimSpace(line)
+		if line == "" {
+			continue
+		}
+		name, value, ok := strings.Cut(line, "=")
+		if !ok {
+			return nil, fmt.Errorf("%s:%d: expected <name> = <value>", path, lineNo)
+		}
+		settings = append(settings, Setting{
+			Name:  strings.TrimSpace(name),
+			Value: strings.TrimSpace(value),
+			Line:  lineNo,
+		})
+	}
+	return settings, scanner.Err()
+}
@@ -0,0 +1,31 @@
+package configdir
+
+import (
+	"os"
+	"path/filepath"
+)
+
+// envOverride lets users (and scripted runs) point the app at another directory.
+const envOverride = "POMODOROFACTORY_HOME"
+
+// Dir returns the directory holding user data such as sound packs.
+// Defaults to <os.UserConfigDir>/pomodorofactory (e.g. ~/.config/pomodorofactory).
+func Dir() (string, error) {
+	if dir := os.Getenv(envOverride); dir != "" {
+		return dir, nil
+	}
+	base, err := os.UserConfigDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(base, "pomodorofactory"), nil
+}
+
+// Path joins elem onto the config directory.
+func Path(elem ...string) (string, error) {
+	dir, err := Dir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(append([]string{dir}, elem...)...), nil
+}
@@ -14,6 +14,9 @@ const (
 	// Extra columns between pillar and art area for arm tip + sparks + gap
 	// Layout on active row: [├][arm ───>][spark][spark][ ][content...]
 	craneOverhead = 4 // > (1) + sparks (2) + gap (1)
+
+	// Animation frames advance every this many Animate calls (50ms ticks)
+	ticksPerFrame = 8
 )
 
 var sparkChars = []rune{'*', '#', '@', '%', '&'}
@@ -21,6 +24,22 @@ var sparkColor = []color.Attribute{color.FgHiYellow}
 var pillarColor = []color.Attribute{color.FgHiWhite}
 var armColor = []color.Attribute{color.FgHiWhite}
 
+// Upgraded looks, earned over time
+var brightSparkChars = []rune{'*', '+', '✦', '✧', '⋆'}
+var brightSparkColors = [][]color.Attribute{
+	{color.Bold, color.FgHiYellow},
+	{color.Bold, color.FgHiWhite},
+	{color.Bold, color.FgHiCyan},
+}
+var goldColor = []color.Attribute{color.Bold, color.FgYellow}
+
+// Upgrades are cosmetic improvements of the factory.
+type Upgrades struct {
+	BrightSparks bool // a livelier spark palette, also in celebrations
+	SecondArm    bool // a feeder arm hangs above the welding arm
+	GoldenPillar bool
+}
+
 var celebrationColors = [][]color.Attribute{
 	{color.FgHiYellow},
 	{color.FgHiGreen},
@@ -32,33 +51,48 @@ var celebrationColors = [][]color.Attribute{
 type factoryscene struct {
 	// Full colored art (all rows, all columns)
 	art [][]runecolor.ColoredRune
-	// Indices of rows that have non-space content, ordered top-to-bottom
-	bodyRows []int
-	// For each bodyRow: column indices of non-space chars (left to right)
-	rowCells [][]int
-	// First non-space column index per body row (for arm length)
-	rowFirstCol []int
+	// Rows that have non-space content, in build order (bottom row first)
+	rows []Row
 
 	// Offset from frame col 0 to art col 0
 	contentOffset int
 
+	// Body rows welded this session, in build order (0 = bottom row):
+	// [sectionStart, sectionEnd). Rows below sectionStart stand already.
+	sectionStart int
+	sectionEnd   int
+
+	// Animation cycled once the whole art is built, and its body rows
+	frames    [][][]runecolor.ColoredRune
+	frameRows [][]Row
+	animTick  int
+	animFrame int
+
+	style        BuildStyle
+	upgrades     Upgrades
+	silhouette   bool                // the product is locked: show its outline only
+	partySparks  []rune              // the product's own celebration sparks
+	partyColors  [][]color.Attribute // and their colors
 	currentFrame [][]runecolor.ColoredRune
 	width        int
 	height       int
 	progress     float64
 	sparkTick    int
+	rng          *rand.Rand // spark glyph flicker
 }
 
-func MakeFactoryScene(products []*product.Product) *factoryscene {
+func MakeFactoryScene(products []*product.Product, rng *rand.Rand) *factoryscene {
 	// Compute max dimensions across all products (canvas is fixed at construction)
 	maxArtWidth, maxArtHeight := 0, 0
 	for _, p := range products {
-		if len(p.Art) > maxArtHeight {
-			maxArtHeight = len(p.Art)
-		}
-		for _, row := range p.Art {
-			if len(row) > maxArtWidth {
-				maxArtWidth = len(row)
+		for _, art := range append([][][]runecolor.ColoredRune{p.Art}, p.Frames...) {
+			if len(art) > maxArtHeight {
+				maxArtHeight = len(art)
+			}
+			for _, row := range art {
+				if len(row) > maxArtWidth {
+					maxArtWidth = len(row)
+				}
 			}
 		}
 	}
@@ -70,6 +104,8 @@ func MakeFactoryScene(products []*product.Product) *factoryscene {
 		width:         contentOffset + maxArtWidth,
 		height:        maxArtHeight,
 		progress:      0,
+		style:         DefaultStyle,
+		rng:           rng,
 	}
 	f.LoadArt(products[0].Art)
 	return f
@@ -79,38 +115,107 @@ func MakeFactoryScene(products []*product.Product) *factoryscene {
 // Canvas dimensions (width/height) are unchanged — fixed at construction.
 func (f *factoryscene) LoadArt(art [][]runecolor.ColoredRune) {
 	f.art = art
+	f.rows = bodyRows(art)
+	f.frames, f.frameRows = nil, nil
+	f.sectionStart = 0
+	f.sectionEnd = len(f.rows)
+	f.silhouette = false
+	f.partySparks, f.partyColors = nil, nil
 
-	// Re-compute body rows
-	var bodyRows []int
-	for i, row := range art {
-		for _, cr := range row {
+	f.progress = 0
+	f.sparkTick = 0
+	f.rebuildFrame()
+}
+
+// bodyRows finds the rows of art with content and their cells, bottom to top.
+func bodyRows(art [][]runecolor.ColoredRune) []Row {
+	var rows []Row
+	for line := len(art) - 1; line >= 0; line-- {
+		var cells []int
+		for col, cr := range art[line] {
 			if cr.Symbol != ' ' {
-				bodyRows = append(bodyRows, i)
-				break
+				cells = append(cells, col)
 			}
 		}
-	}
-	f.bodyRows = bodyRows
-
-	// Re-compute rowCells and rowFirstCol
-	rowCells := make([][]int, len(bodyRows))
-	rowFirstCol := make([]int, len(bodyRows))
-	for bi, rowIdx := range bodyRows {
-		rowFirstCol[bi] = -1
-		for col, cr := range art[rowIdx] {
-			if cr.Symbol != ' ' {
-				rowCells[bi] = append(rowCells[bi], col)
-				if rowFirstCol[bi] == -1 {
-					rowFirstCol[bi] = col
-				}
-			}
+		if len(cells) > 0 {
+			rows = append(rows, Row{Line: line, Cells: cells})
 		}
 	}
-	f.rowCells = rowCells
-	f.rowFirstCol = rowFirstCol
+	return rows
+}
 
-	f.progress = 0
-	f.sparkTick = 0
+// SetAnimation sets frames to cycle once the art is completely built.
+// Call after LoadArt, which clears them.
+func (f *factoryscene) SetAnimation(frames [][][]runecolor.ColoredRune) {
+	f.frames = frames
+	f.frameRows = make([][]Row, len(frames))
+	for i, frame := range frames {
+		f.frameRows[i] = bodyRows(frame)
+	}
+	f.animTick, f.animFrame = 0, 0
+	f.rebuildFrame()
+}
+
+// Animate advances the animation by one tick and reports whether the
+// picture changed. It does nothing until the whole art is built.
+func (f *factoryscene) Animate() bool {
+	if !f.animating() {
+		return false
+	}
+	f.animTick++
+	frame := f.animTick / ticksPerFrame % len(f.frames)
+	if frame == f.animFrame {
+		return false
+	}
+	f.animFrame = frame
+	f.rebuildFrame()
+	return true
+}
+
+// animating reports whether the finished art is replaced by animation frames.
+func (f *factoryscene) animating() bool {
+	return len(f.frames) > 0 && f.progress >= 1 && f.sectionEnd == len(f.rows)
+}
+
+// SetStyle picks how the build is animated.
+func (f *factoryscene) SetStyle(style BuildStyle) {
+	f.style = style
+	f.rebuildFrame()
+}
+
+// SetUpgrades changes the factory's looks.
+func (f *factoryscene) SetUpgrades(u Upgrades) {
+	f.upgrades = u
+	f.rebuildFrame()
+}
+
+// SetSilhouette shows only a dim outline of the whole art, for a product
+// that can't be built yet. Call after LoadArt, which clears it.
+func (f *factoryscene) SetSilhouette(on bool) {
+	f.silhouette = on
+	f.rebuildFrame()
+}
+
+// SetCelebration gives the celebration the product's own spark glyphs and
+// colors; nil keeps the factory's. Call after LoadArt, which clears them.
+func (f *factoryscene) SetCelebration(sparks []rune, colors [][]color.Attribute) {
+	f.partySparks, f.partyColors = sparks, colors
+}
+
+// SetSection limits the build to section n (0-based) of total contiguous
+// slices of rows, for mega-builds that take several pomodoros. The sections
+// below n are shown finished.
+func (f *factoryscene) SetSection(n, total int) {
+	numBodyRows := len(f.rows)
+	if total > numBodyRows {
+		total = numBodyRows
+	}
+	if total < 1 || n < 0 || n >= total {
+		f.sectionStart, f.sectionEnd = 0, numBodyRows
+	} else {
+		f.sectionStart = n * numBodyRows / total
+		f.sectionEnd = (n + 1) * numBodyRows / total
+	}
 	f.rebuildFrame()
 }
 
@@ -133,8 +238,9 @@ func (f *factoryscene) SetProgress(p float64) {
 	f.rebuildFrame()
 }
 
+// rebuildFrame clears the canvas and lets the build style draw the
+// current progress on it.
 func (f *factoryscene) rebuildFrame() {
-	numBodyRows := len(f.bodyRows)
 	emptyAttr := make([]color.Attribute, 0)
 
 	// Allocate frame
@@ -146,131 +252,35 @@ func (f *factoryscene) rebuildFrame() {
 		}
 	}
 
-	// Determine active body row index (in build order: bottom to top)
-	// bodyRows is ordered top-to-bottom, so build order reverses it
-	// buildIndex 0 = bottom row, buildIndex numBodyRows-1 = top row
-	var activeBuildIdx int
-	var colsRevealed int
-	done := f.progress >= 1.0
-
-	if done {
-		activeBuildIdx = numBodyRows // past all rows
-	} else if f.progress <= 0 {
-		activeBuildIdx = -1 // not started
-	} else {
-		scaled := f.progress * float64(numBodyRows)
-		activeBuildIdx = int(scaled)
-		if activeBuildIdx >= numBodyRows {
-			activeBuildIdx = numBodyRows - 1
-		}
-		colProgress := scaled - float64(activeBuildIdx)
-		activeBodyIdx := numBodyRows - 1 - activeBuildIdx
-		numCells := len(f.rowCells[activeBodyIdx])
-		colsRevealed = int(colProgress * float64(numCells))
-		if colsRevealed > numCells {
-			colsRevealed = numCells
-		}
+	frame := &Frame{
+		Cells:    f.currentFrame,
+		Art:      f.art,
+		Rows:     f.rows,
+		Offset:   f.contentOffset,
+		From:     f.sectionStart,
+		To:       f.sectionEnd,
+		Tick:     f.sparkTick,
+		Rng:      f.rng,
+		Upgrades: f.upgrades,
 	}
-
-	// Draw each row
-	for row := 0; row < f.height; row++ {
-		// Column 0: pillar
-		f.currentFrame[row][0] = runecolor.ColoredRune{Symbol: '│', ColorAttributes: pillarColor}
-
-		// Find which body row index this is (if any)
-		bodyIdx := -1
-		for bi, ri := range f.bodyRows {
-			if ri == row {
-				bodyIdx = bi
-				break
-			}
-		}
-
-		if bodyIdx == -1 {
-			continue
-		}
-
-		// Build index for this row (0 = bottom row in build order)
-		buildIdx := numBodyRows - 1 - bodyIdx
-
-		if done || buildIdx < activeBuildIdx {
-			// Completed row: full art content at natural positions
-			f.copyArtRow(row, bodyIdx)
-		} else if buildIdx == activeBuildIdx {
-			// Active build row: arm + sparks + partial content
-			f.drawActiveRow(row, bodyIdx, colsRevealed)
-		}
-		// buildIdx > activeBuildIdx: not yet built, stays empty (just pillar)
-	}
-}
-
-// copyArtRow copies all non-space chars of a body row at natural positions + contentOffset
-func (f *factoryscene) copyArtRow(row int, bodyIdx int) {
-	artRow := f.art[f.bodyRows[bodyIdx]]
-	for _, artCol := range f.rowCells[bodyIdx] {
-		frameCol := f.contentOffset + artCol
-		if frameCol < f.width && artCol < len(artRow) {
-			f.currentFrame[row][frameCol] = artRow[artCol]
-		}
+	if f.animating() {
+		frame.Art, frame.Rows = f.frames[f.animFrame], f.frameRows[f.animFrame]
+		frame.From, frame.To = 0, len(frame.Rows)
 	}
-}
-
-// drawActiveRow draws the crane arm, sparks, and partially revealed content
-func (f *factoryscene) drawActiveRow(row int, bodyIdx int, colsRevealed int) {
-	firstCol := f.rowFirstCol[bodyIdx]
-	numCells := len(f.rowCells[bodyIdx])
-
-	// Pillar junction
-	f.currentFrame[row][0] = runecolor.ColoredRune{Symbol: '├', ColorAttributes: pillarColor}
-
-	// Art content starts at contentOffset + firstCol in frame space.
-	// The crane mechanism occupies the space before that:
-	//   [├][── arm ──][>][spark][spark][ gap ][content...]
-	//
-	// sparkEnd = contentOffset + firstCol (where content begins)
-	// sparkStart = sparkEnd - 3 (2 sparks + 1 gap)
-	// armTip (>) = sparkStart - 1
-	// arm (──) = pillar+1 to armTip-1
-
-	contentStart := f.contentOffset + firstCol
-	sparkStart := contentStart - 3 // 2 sparks + 1 gap before content
-	if sparkStart < 1 {
-		sparkStart = 1
-	}
-
-	// Draw arm: from col 1 to sparkStart-1
-	for col := 1; col < sparkStart && col < f.width; col++ {
-		f.currentFrame[row][col] = runecolor.ColoredRune{Symbol: '─', ColorAttributes: armColor}
-	}
-
-	// Arm tip >
-	if sparkStart-1 >= 1 && sparkStart-1 < f.width {
-		f.currentFrame[row][sparkStart-1] = runecolor.ColoredRune{Symbol: '>', ColorAttributes: armColor}
-	}
-
-	// Sparks (only while still building this row)
-	if colsRevealed < numCells {
-		for i := 0; i < 2; i++ {
-			pos := sparkStart + i
-			if pos >= 1 && pos < f.width {
-				ch := sparkChars[rand.Intn(len(sparkChars))]
-				f.currentFrame[row][pos] = runecolor.ColoredRune{Symbol: ch, ColorAttributes: sparkColor}
+	f.style.Draw(frame, f.progress)
+	if f.silhouette {
+		for _, r := range f.rows {
+			for _, col := range r.Cells {
+				if frame.IsEmpty(r.Line, f.contentOffset+col) {
+					frame.Set(r.Line, f.contentOffset+col, runecolor.ColoredRune{Symbol: '░', ColorAttributes: silhouetteColor})
+				}
 			}
 		}
-		// Gap (space) is already there from initialization
-	}
-
-	// Revealed content at natural positions
-	artRow := f.art[f.bodyRows[bodyIdx]]
-	for i := 0; i < colsRevealed && i < numCells; i++ {
-		artCol := f.rowCells[bodyIdx][i]
-		frameCol := f.contentOffset + artCol
-		if frameCol < f.width && artCol < len(artRow) {
-			f.currentFrame[row][frameCol] = artRow[artCol]
-		}
 	}
 }
 
+var silhouetteColor = []color.Attribute{color.FgHiBlack}
+
 func (f *factoryscene) Width() int {
 	return f.width
 }
@@ -280,19 +290,47 @@ func (f *factoryscene) Height() int {
 }
 
 // SetCelebrating overlays random colorful sparks on the completed art.
-func (f *factoryscene) SetCelebrating(tick int) {
+// A grand celebration, for finishing a mega-build, is denser and spills
+// sparks into the sky around the art.
+func (f *factoryscene) SetCelebrating(tick int, grand bool) {
 	f.progress = 1.0
 	f.rebuildFrame()
 
+	density := 0.15
+	if grand {
+		density = 0.35
+	}
 	rng := rand.New(rand.NewSource(int64(tick)))
-	for bi, rowIdx := range f.bodyRows {
-		for _, artCol := range f.rowCells[bi] {
-			if rng.Float64() < 0.15 {
+	chars, colors := sparkChars, celebrationColors
+	if f.upgrades.BrightSparks {
+		chars, colors = brightSparkChars, append(brightSparkColors, celebrationColors...)
+	}
+	if len(f.partySparks) > 0 {
+		chars = f.partySparks
+	}
+	if len(f.partyColors) > 0 {
+		colors = f.partyColors
+	}
+	if grand {
+		for row := range f.currentFrame {
+			for col := f.contentOffset; col < f.width; col++ {
+				if f.currentFrame[row][col].Symbol == ' ' && rng.Float64() < 0.06 {
+					f.currentFrame[row][col] = runecolor.ColoredRune{
+						Symbol:          chars[rng.Intn(len(chars))],
+						ColorAttributes: colors[rng.Intn(len(colors))],
+					}
+				}
+			}
+		}
+	}
+	for _, r := range f.rows[:f.sectionEnd] {
+		for _, artCol := range r.Cells {
+			if rng.Float64() < density {
 				frameCol := f.contentOffset + artCol
 				if frameCol < f.width {
-					ch := sparkChars[rng.Intn(len(sparkChars))]
-					clr := celebrationColors[rng.Intn(len(celebrationColors))]
-					f.currentFrame[rowIdx][frameCol] = runecolor.ColoredRune{
+					ch := chars[rng.Intn(len(chars))]
+					clr := colors[rng.Intn(len(colors))]
+					f.currentFrame[r.Line][frameCol] = runecolor.ColoredRune{
 						Symbol:          ch,
 						ColorAttributes: clr,
 					}
@@ -0,0 +1,337 @@
+package factoryscene
+
+import (
+	"math/rand"
+	"sort"
+	"strings"
+
+	"github.com/anschnapp/pomodorofactory/pkg/runecolor"
+	"github.com/fatih/color"
+)
+
+// BuildStyle animates a product being built: it draws the frame for a
+// progress from 0 (nothing of the section built) to 1 (section done).
+type BuildStyle interface {
+	Name() string
+	Draw(f *Frame, progress float64)
+}
+
+// Frame is what a BuildStyle draws on: a blank canvas and the product's
+// art laid out on it. Art line i is canvas line i.
+type Frame struct {
+	Cells  [][]runecolor.ColoredRune // the canvas
+	Art    [][]runecolor.ColoredRune
+	Rows   []Row // art rows with content, in build order (bottom row first)
+	Offset int   // canvas column of art column 0
+	// From and To bound the rows built this session; a mega-build builds
+	// one slice at a time and the rows below From stand already.
+	From, To int
+	Tick     int        // advances with every progress update
+	Rng      *rand.Rand // for flicker
+	Upgrades Upgrades   // earned looks; styles may ignore them
+}
+
+// Row is one art row with content: its line and its non-space columns,
+// left to right.
+type Row struct {
+	Line  int
+	Cells []int
+}
+
+// Width returns the canvas width.
+func (f *Frame) Width() int {
+	if len(f.Cells) == 0 {
+		return 0
+	}
+	return len(f.Cells[0])
+}
+
+// Set draws on the canvas; anything outside it is dropped.
+func (f *Frame) Set(line, col int, cr runecolor.ColoredRune) {
+	if line >= 0 && line < len(f.Cells) && col >= 0 && col < len(f.Cells[line]) {
+		f.Cells[line][col] = cr
+	}
+}
+
+// IsEmpty reports whether nothing is drawn at a canvas cell.
+func (f *Frame) IsEmpty(line, col int) bool {
+	return line >= 0 && line < len(f.Cells) && col >= 0 && col < len(f.Cells[line]) && f.Cells[line][col].Symbol == ' '
+}
+
+// Spark returns a random spark glyph in the factory's spark palette.
+func (f *Frame) Spark() runecolor.ColoredRune {
+	if f.Upgrades.BrightSparks {
+		return runecolor.ColoredRune{
+			Symbol:          brightSparkChars[f.Rng.Intn(len(brightSparkChars))],
+			ColorAttributes: brightSparkColors[f.Rng.Intn(len(brightSparkColors))],
+		}
+	}
+	return runecolor.ColoredRune{Symbol: sparkChars[f.Rng.Intn(len(sparkChars))], ColorAttributes: sparkColor}
+}
+
+// PillarColor is the crane pillar's color.
+func (f *Frame) PillarColor() []color.Attribute {
+	if f.Upgrades.GoldenPillar {
+		return goldColor
+	}
+	return pillarColor
+}
+
+// Reveal draws the first n cells of build row i.
+func (f *Frame) Reveal(i, n int) {
+	r := f.Rows[i]
+	for _, col := range r.Cells[:min(n, len(r.Cells))] {
+		f.Set(r.Line, f.Offset+col, f.Art[r.Line][col])
+	}
+}
+
+// RevealRows draws build rows [from, to) completely.
+func (f *Frame) RevealRows(from, to int) {
+	for i := from; i < to; i++ {
+		f.Reveal(i, len(f.Rows[i].Cells))
+	}
+}
+
+// Split maps progress over the section's rows: the build row being worked
+// on and how far along it is. At progress 1 it returns To.
+func (f *Frame) Split(progress float64) (row int, frac float64) {
+	n := f.To - f.From
+	if progress >= 1 {
+		return f.To, 0
+	}
+	if progress <= 0 || n == 0 {
+		return f.From, 0
+	}
+	scaled := progress * float64(n)
+	i := min(int(scaled), n-1)
+	return f.From + i, scaled - float64(i)
+}
+
+// Styles lists the selectable build styles.
+var Styles = []BuildStyle{crane{}, printer{}, conveyor{}, dissolve{}, pour{}, fill{}}
+
+// DefaultStyle is the crane welding rows from the bottom up.
+var DefaultStyle BuildStyle = crane{}
+
+// StyleByName looks up a build style. Unknown names return DefaultStyle and false.
+func StyleByName(name string) (BuildStyle, bool) {
+	for _, s := range Styles {
+		if strings.EqualFold(s.Name(), name) {
+			return s, true
+		}
+	}
+	return DefaultStyle, false
+}
+
+// StyleNames lists the build style names.
+func StyleNames() []string {
+	names := make([]string, len(Styles))
+	for i, s := range Styles {
+		names[i] = s.Name()
+	}
+	return names
+}
+
+// crane welds row by row, bottom to top, left to right; the arm reaches
+// out from the pillar and sparks fly at the tip.
+type crane struct{}
+
+func (crane) Name() string { return "crane" }
+
+func (crane) Draw(f *Frame, progress float64) {
+	for line := range f.Cells {
+		f.Set(line, 0, runecolor.ColoredRune{Symbol: '│', ColorAttributes: f.PillarColor()})
+	}
+	row, frac := f.Split(progress)
+	f.RevealRows(0, row)
+	if progress <= 0 || row >= f.To {
+		return
+	}
+	r := f.Rows[row]
+	numCells := len(r.Cells)
+	colsRevealed := int(frac * float64(numCells))
+
+	// Pillar junction
+	f.Set(r.Line, 0, runecolor.ColoredRune{Symbol: '├', ColorAttributes: f.PillarColor()})
+
+	// Art content starts at Offset + first cell in frame space.
+	// The crane mechanism occupies the space before that:
+	//   [├][── arm ──][>][spark][spark][ gap ][content...]
+	contentStart := f.Offset + r.Cells[0]
+	sparkStart := max(contentStart-3, 1) // 2 sparks + 1 gap before content
+
+	// Arm from the pillar to the tip
+	for col := 1; col < sparkStart-1; col++ {
+		f.Set(r.Line, col, runecolor.ColoredRune{Symbol: '─', ColorAttributes: armColor})
+	}
+	if sparkStart-1 >= 1 {
+		f.Set(r.Line, sparkStart-1, runecolor.ColoredRune{Symbol: '>', ColorAttributes: armColor})
+	}
+
+	// Second arm: a feeder hanging over the row above, holding the next piece
+	if f.Upgrades.SecondArm && colsRevealed < numCells && r.Line > 0 && f.IsEmpty(r.Line-1, 1) {
+		f.Set(r.Line-1, 0, runecolor.ColoredRune{Symbol: '├', ColorAttributes: f.PillarColor()})
+		for col := 1; col < sparkStart+1; col++ {
+			f.Set(r.Line-1, col, runecolor.ColoredRune{Symbol: '─', ColorAttributes: armColor})
+		}
+		f.Set(r.Line-1, sparkStart+1, runecolor.ColoredRune{Symbol: '┐', ColorAttributes: armColor})
+	}
+
+	// Sparks (only while still building this row)
+	if colsRevealed < numCells {
+		for i := 0; i < 2; i++ {
+			f.Set(r.Line, sparkStart+i, f.Spark())
+		}
+	}
+
+	f.Reveal(row, colsRevealed)
+}
+
+// printer lays down each row left to right under a nozzle riding a gantry,
+// like a 3D printer.
+type printer struct{}
+
+var gantryColor = []color.Attribute{color.FgHiBlack}
+var hotColor = []color.Attribute{color.FgHiRed}
+
+func (printer) Name() string { return "printer" }
+
+func (printer) Draw(f *Frame, progress float64) {
+	row, frac := f.Split(progress)
+	f.RevealRows(0, row)
+	if progress <= 0 || row >= f.To {
+		return
+	}
+	r := f.Rows[row]
+	printed := int(frac * float64(len(r.Cells)))
+	f.Reveal(row, printed)
+
+	col := f.Offset + r.Cells[printed]
+	f.Set(r.Line, col, runecolor.ColoredRune{Symbol: '•', ColorAttributes: hotColor})
+	gantry := r.Line - 1
+	for c := f.Offset; c < f.Width(); c++ {
+		if f.IsEmpty(gantry, c) {
+			f.Set(gantry, c, runecolor.ColoredRune{Symbol: '─', ColorAttributes: gantryColor})
+		}
+	}
+	f.Set(gantry, col, runecolor.ColoredRune{Symbol: '▼', ColorAttributes: armColor})
+}
+
+// conveyor brings each piece in from the right on a moving belt and sets
+// it in place, row by row from the bottom.
+type conveyor struct{}
+
+var beltChars = []rune{'=', '-'}
+
+func (conveyor) Name() string { return "conveyor" }
+
+func (conveyor) Draw(f *Frame, progress float64) {
+	row, frac := f.Split(progress)
+	f.RevealRows(0, row)
+	if progress <= 0 || row >= f.To {
+		return
+	}
+	r := f.Rows[row]
+	scaled := frac * float64(len(r.Cells))
+	placed := int(scaled)
+	f.Reveal(row, placed)
+
+	target := f.Offset + r.Cells[placed]
+	for c := target + 1; c < f.Width(); c++ {
+		if f.IsEmpty(r.Line, c) {
+			f.Set(r.Line, c, runecolor.ColoredRune{Symbol: beltChars[(c+f.Tick)%len(beltChars)], ColorAttributes: gantryColor})
+		}
+	}
+	start := f.Width() - 1
+	pos := start - int(float64(start-target)*(scaled-float64(placed)))
+	f.Set(r.Line, pos, f.Art[r.Line][r.Cells[placed]])
+}
+
+// dissolve materializes the section cell by cell in scattered order, with
+// a shimmer where the next cells are about to appear.
+type dissolve struct{}
+
+var noiseChars = []rune{'░', '▒'}
+
+func (dissolve) Name() string { return "dissolve" }
+
+func (dissolve) Draw(f *Frame, progress float64) {
+	f.RevealRows(0, f.From)
+	type cell struct{ line, col int }
+	var cells []cell
+	for _, r := range f.Rows[f.From:f.To] {
+		for _, col := range r.Cells {
+			cells = append(cells, cell{r.Line, col})
+		}
+	}
+	// The same order every frame, so revealed cells stay put
+	order := rand.New(rand.NewSource(int64(len(cells)))).Perm(len(cells))
+	shown := int(progress * float64(len(cells)))
+	shimmer := shown + len(cells)/12 + 1
+	for k, idx := range order {
+		c := cells[idx]
+		art := f.Art[c.line][c.col]
+		switch {
+		case k < shown:
+			f.Set(c.line, f.Offset+c.col, art)
+		case progress > 0 && k < shimmer:
+			ch := noiseChars[f.Rng.Intn(len(noiseChars))]
+			f.Set(c.line, f.Offset+c.col, runecolor.ColoredRune{Symbol: ch, ColorAttributes: art.ColorAttributes})
+		}
+	}
+}
+
+// pour fills the product like a mold: a stream runs down from the top and
+// each row fills outward from where it lands, bottom row first.
+type pour struct{}
+
+var streamChars = []rune{'│', '¦'}
+
+func (pour) Name() string { return "pour" }
+
+func (pour) Draw(f *Frame, progress float64) {
+	row, frac := f.Split(progress)
+	f.RevealRows(0, row)
+	if progress <= 0 || row >= f.To {
+		return
+	}
+	artWidth := 0
+	for _, line := range f.Art {
+		artWidth = max(artWidth, len(line))
+	}
+	center := artWidth / 2
+
+	r := f.Rows[row]
+	order := append([]int(nil), r.Cells...)
+	sort.SliceStable(order, func(i, j int) bool {
+		return abs(order[i]-center) < abs(order[j]-center)
+	})
+	for _, col := range order[:int(frac*float64(len(order)))] {
+		f.Set(r.Line, f.Offset+col, f.Art[r.Line][col])
+	}
+
+	liquid := f.Art[r.Line][order[0]].ColorAttributes
+	for line := 0; line <= r.Line; line++ {
+		if f.IsEmpty(line, f.Offset+center) {
+			ch := streamChars[(line+f.Tick)%len(streamChars)]
+			f.Set(line, f.Offset+center, runecolor.ColoredRune{Symbol: ch, ColorAttributes: liquid})
+		}
+	}
+}
+
+// fill is the original tomato animation: whole rows appear from the
+// bottom up, no machinery.
+type fill struct{}
+
+func (fill) Name() string { return "fill" }
+
+func (fill) Draw(f *Frame, progress float64) {
+	f.RevealRows(0, f.From+int(progress*float64(f.To-f.From)))
+}
+
+func abs(n int) int {
+	if n < 0 {
+		return -n
+	}
+	return n
+}
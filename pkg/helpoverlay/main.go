@@ -0,0 +1,145 @@
+package helpoverlay
+
+import (
+	"github.com/anschnapp/pomodorofactory/pkg/runecolor"
+	"github.com/fatih/color"
+)
+
+// Row is one line of a section: a key (or command) and what it does.
+// Rows without a description are shown as plain text.
+type Row struct {
+	Key         string
+	Description string
+}
+
+// Section is a titled block of rows.
+type Section struct {
+	Title string
+	Rows  []Row
+}
+
+const footer = "press any key to close"
+
+type helpoverlay struct {
+	width              int
+	height             int
+	asciRepresentation [][]runecolor.ColoredRune
+}
+
+func MakeHelpOverlay() *helpoverlay {
+	return &helpoverlay{}
+}
+
+// columnGap separates the two columns of sections.
+const columnGap = 4
+
+// SetContent lays out the title and sections; the overlay sizes itself to
+// fit. Sections flow into two columns of about equal height so the overlay
+// fits the canvas.
+func (h *helpoverlay) SetContent(title string, sections []Section) {
+	blocks := make([][][]runecolor.ColoredRune, len(sections))
+	for i, s := range sections {
+		blocks[i] = sectionLines(s)
+	}
+
+	// Split where the taller column is shortest
+	split, best := len(blocks), -1
+	for k := 1; k <= len(blocks); k++ {
+		height := max(columnHeight(blocks[:k]), columnHeight(blocks[k:]))
+		if best < 0 || height < best {
+			split, best = k, height
+		}
+	}
+	left, right := stack(blocks[:split]), stack(blocks[split:])
+	leftWidth := 0
+	for _, l := range left {
+		leftWidth = max(leftWidth, len(l))
+	}
+
+	lines := [][]runecolor.ColoredRune{colored(title, []color.Attribute{color.Bold, color.FgHiYellow})}
+	for i := 0; i < max(len(left), len(right)); i++ {
+		var line []runecolor.ColoredRune
+		if i < len(left) {
+			line = append(line, left[i]...)
+		}
+		if i < len(right) {
+			for len(line) < leftWidth+columnGap {
+				line = append(line, runecolor.ColoredRune{Symbol: ' '})
+			}
+			line = append(line, right[i]...)
+		}
+		lines = append(lines, line)
+	}
+	lines = append(lines, nil, colored(footer, []color.Attribute{color.Faint}))
+
+	h.width = 0
+	for _, l := range lines {
+		h.width = max(h.width, len(l))
+	}
+	h.height = len(lines)
+	h.asciRepresentation = lines
+}
+
+// sectionLines renders a section preceded by a blank line, keys aligned.
+func sectionLines(s Section) [][]runecolor.ColoredRune {
+	keyWidth := 0
+	for _, r := range s.Rows {
+		if r.Description != "" {
+			keyWidth = max(keyWidth, len([]rune(r.Key)))
+		}
+	}
+	lines := [][]runecolor.ColoredRune{nil, colored(s.Title, []color.Attribute{color.FgHiCyan})}
+	for _, r := range s.Rows {
+		if r.Description == "" {
+			lines = append(lines, colored("  "+r.Key, nil))
+			continue
+		}
+		key := []rune(r.Key)
+		for len(key) < keyWidth {
+			key = append(key, ' ')
+		}
+		line := colored("  "+string(key), []color.Attribute{color.FgHiGreen})
+		line = append(line, colored("  "+r.Description, nil)...)
+		lines = append(lines, line)
+	}
+	return lines
+}
+
+func columnHeight(blocks [][][]runecolor.ColoredRune) int {
+	height := 0
+	for _, b := range blocks {
+		height += len(b)
+	}
+	return height
+}
+
+func stack(blocks [][][]runecolor.ColoredRune) [][]runecolor.ColoredRune {
+	var lines [][]runecolor.ColoredRune
+	for _, b := range blocks {
+		lines = append(lines, b...)
+	}
+	return lines
+}
+
+func colored(text string, attrs []color.Attribute) []runecolor.ColoredRune {
+	return runecolor.ConvertRunesToColoredRunes([]rune(text), nil, attrs)
+}
+
+func (h *helpoverlay) Width() int {
+	return h.width
+}
+
+func (h *helpoverlay) Height() int {
+	return h.height
+}
+
+func (h *helpoverlay) Render(subview [][]runecolor.ColoredRune) {
+	for i := range subview {
+		for j := range subview[i] {
+			subview[i][j] = runecolor.ColoredRune{Symbol: ' '}
+		}
+		if i < len(h.asciRepresentation) {
+			copy(subview[i], h.asciRepresentation[i])
+		}
+	}
+}
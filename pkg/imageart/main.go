@@ -0,0 +1,107 @@
+package imageart
+
+import (
+	"image"
+	"math"
+	"strings"
+
+	"github.com/anschnapp/pomodorofactory/pkg/product"
+)
+
+// ramp orders glyphs from faint to dense; brighter cells get denser glyphs
+// since the factory runs on a dark terminal.
+var ramp = []rune(".:-=+*#%@")
+
+// levels is how many steps each color channel is quantized to.
+const levels = 6
+
+// Options control the conversion.
+type Options struct {
+	Width, Height int
+	// KeepBackground draws cells that match the image's corner color too;
+	// by default they are left blank, as are transparent ones.
+	KeepBackground bool
+}
+
+type rgba struct{ r, g, b, a float64 }
+
+// Convert turns an image into art with one glyph and one truecolor per
+// cell, averaging the pixels each cell covers.
+func Convert(img image.Image, opts Options) (art, colors []string) {
+	bounds := img.Bounds()
+	background := average(img, image.Rect(bounds.Min.X, bounds.Min.Y, bounds.Min.X+1, bounds.Min.Y+1))
+	for row := 0; row < opts.Height; row++ {
+		var line strings.Builder
+		var cellColors []string
+		for col := 0; col < opts.Width; col++ {
+			cell := image.Rect(
+				bounds.Min.X+col*bounds.Dx()/opts.Width,
+				bounds.Min.Y+row*bounds.Dy()/opts.Height,
+				bounds.Min.X+(col+1)*bounds.Dx()/opts.Width,
+				bounds.Min.Y+(row+1)*bounds.Dy()/opts.Height,
+			)
+			c := average(img, cell)
+			if c.a < 0.5 || !opts.KeepBackground && background.a >= 0.5 && distance(c, background) < 0.1 {
+				line.WriteRune(' ')
+				cellColors = append(cellColors, product.NoColor)
+				continue
+			}
+			line.WriteRune(glyph(c))
+			cellColors = append(cellColors, product.HexColor(quantize(c.r), quantize(c.g), quantize(c.b)))
+		}
+		// Blank cells at the end of a row are dropped, as in hand-drawn art
+		art = append(art, strings.TrimRight(line.String(), " "))
+		colors = append(colors, strings.Join(cellColors[:len([]rune(art[row]))], " "))
+	}
+	return trimRows(art, colors)
+}
+
+// average is the mean color of the pixels in r, weighted by alpha, and
+// their mean alpha. Channels are 0–1.
+func average(img image.Image, r image.Rectangle) rgba {
+	if r.Empty() {
+		r.Max = r.Min.Add(image.Pt(1, 1))
+	}
+	var sum rgba
+	for y := r.Min.Y; y < r.Max.Y; y++ {
+		for x := r.Min.X; x < r.Max.X; x++ {
+			cr, cg, cb, ca := img.At(x, y).RGBA() // alpha-premultiplied, 0–0xffff
+			sum.r += float64(cr) / 0xffff
+			sum.g += float64(cg) / 0xffff
+			sum.b += float64(cb) / 0xffff
+			sum.a += float64(ca) / 0xffff
+		}
+	}
+	n := float64(r.Dx() * r.Dy())
+	if sum.a == 0 {
+		return rgba{}
+	}
+	return rgba{sum.r / sum.a, sum.g / sum.a, sum.b / sum.a, sum.a / n}
+}
+
+func distance(a, b rgba) float64 {
+	return math.Sqrt(((a.r-b.r)*(a.r-b.r) + (a.g-b.g)*(a.g-b.g) + (a.b-b.b)*(a.b-b.b)) / 3)
+}
+
+// glyph picks a glyph by the cell's luminance.
+func glyph(c rgba) rune {
+	lum := 0.2126*c.r + 0.7152*c.g + 0.0722*c.b
+	return ramp[min(int(lum*float64(len(ramp))), len(ramp)-1)]
+}
+
+// quantize rounds a 0–1 channel to one of the levels, as 0–255.
+func quantize(v float64) uint8 {
+	step := math.Round(min(max(v, 0), 1) * (levels - 1))
+	return uint8(step * 255 / (levels - 1))
+}
+
+// trimRows drops empty rows above and below the picture.
+func trimRows(art, colors []string) ([]string, []string) {
+	for len(art) > 0 && art[0] == "" {
+		art, colors = art[1:], colors[1:]
+	}
+	for len(art) > 0 && art[len(art)-1] == "" {
+		art, colors = art[:len(art)-1], colors[:len(colors)-1]
+	}
+	return art, colors
+}
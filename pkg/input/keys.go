@@ -0,0 +1,214 @@
+package input
+
+import (
+	"fmt"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+)
+
+// Key identifies a decoded key. Printable characters and Ctrl/Alt letter
+// combos are KeyRune with the character in KeyEvent.Rune.
+type Key int
+
+const (
+	KeyRune Key = iota
+	KeyEnter
+	KeyTab
+	KeyBackspace
+	KeyEscape
+	KeyUp
+	KeyDown
+	KeyLeft
+	KeyRight
+	KeyHome
+	KeyEnd
+	KeyPageUp
+	KeyPageDown
+	KeyInsert
+	KeyDelete
+	KeyF1
+	KeyF2
+	KeyF3
+	KeyF4
+	KeyF5
+	KeyF6
+	KeyF7
+	KeyF8
+	KeyF9
+	KeyF10
+	KeyF11
+	KeyF12
+	KeyPaste // bracketed paste; the text is in KeyEvent.Paste
+	KeyMouse // mouse report; details are in KeyEvent.Mouse
+)
+
+var keyNames = map[Key]string{
+	KeyEnter:     "enter",
+	KeyTab:       "tab",
+	KeyBackspace: "backspace",
+	KeyEscape:    "esc",
+	KeyUp:        "up",
+	KeyDown:      "down",
+	KeyLeft:      "left",
+	KeyRight:     "right",
+	KeyHome:      "home",
+	KeyEnd:       "end",
+	KeyPageUp:    "pgup",
+	KeyPageDown:  "pgdown",
+	KeyInsert:    "insert",
+	KeyDelete:    "delete",
+	KeyF1:        "f1",
+	KeyF2:        "f2",
+	KeyF3:        "f3",
+	KeyF4:        "f4",
+	KeyF5:        "f5",
+	KeyF6:        "f6",
+	KeyF7:        "f7",
+	KeyF8:        "f8",
+	KeyF9:        "f9",
+	KeyF10:       "f10",
+	KeyF11:       "f11",
+	KeyF12:       "f12",
+	KeyPaste:     "paste",
+	KeyMouse:     "mouse",
+}
+
+// Modifier is a bit set of modifier keys held during a key press.
+type Modifier int
+
+const (
+	ModShift Modifier = 1 << iota
+	ModAlt
+	ModCtrl
+)
+
+// MouseButton identifies the button in a mouse report.
+type MouseButton int
+
+const (
+	MouseLeft MouseButton = iota
+	MouseMiddle
+	MouseRight
+	MouseNone // motion without a button held
+	MouseWheelUp
+	MouseWheelDown
+)
+
+// MouseEvent is a decoded SGR (1006) mouse report.
+type MouseEvent struct {
+	Button  MouseButton
+	X, Y    int  // 0-based cell column and row
+	Release bool // button released (false = pressed or wheel)
+	Motion  bool // reported while moving
+}
+
+// KeyEvent is one decoded key press.
+type KeyEvent struct {
+	Key   Key
+	Rune  rune       // set for KeyRune
+	Mod   Modifier   // modifiers reported by the terminal
+	Paste string     // set for KeyPaste
+	Mouse MouseEvent // set for KeyMouse
+}
+
+// IsClick reports whether the event is a left mouse button press.
+func (e KeyEvent) IsClick() bool {
+	return e.Key == KeyMouse && e.Mouse.Button == MouseLeft && !e.Mouse.Release && !e.Mouse.Motion
+}
+
+// String returns the canonical name of the key, e.g. "q", "ctrl+c",
+// "alt+x", "shift+up", "f5" or "space". Uppercase letters are their own
+// name ("Q") rather than "shift+q".
+func (e KeyEvent) String() string {
+	var sb strings.Builder
+	if e.Mod&ModCtrl != 0 {
+		sb.WriteString("ctrl+")
+	}
+	if e.Mod&ModAlt != 0 {
+		sb.WriteString("alt+")
+	}
+	if e.Mod&ModShift != 0 && e.Key != KeyRune {
+		sb.WriteString("shift+")
+	}
+	if e.Key == KeyRune {
+		if e.Rune == ' ' {
+			sb.WriteString("space")
+		} else {
+			sb.WriteRune(e.Rune)
+		}
+	} else {
+		sb.WriteString(keyNames[e.Key])
+	}
+	return sb.String()
+}
+
+// ctrlKey decodes a C0 control byte into the key it was typed as.
+func ctrlKey(b byte) KeyEvent {
+	switch b {
+	case 0x0d, 0x0a:
+		return KeyEvent{Key: KeyEnter}
+	case 0x09:
+		return KeyEvent{Key: KeyTab}
+	case 0x08, 0x7f:
+		return KeyEvent{Key: KeyBackspace}
+	case 0x1b:
+		return KeyEvent{Key: KeyEscape}
+	case 0x00:
+		return KeyEvent{Key: KeyRune, Rune: ' ', Mod: ModCtrl}
+	}
+	if b < 0x1b {
+		// Ctrl+A (0x01) … Ctrl+Z (0x1a)
+		return KeyEvent{Key: KeyRune, Rune: rune('a' + b - 1), Mod: ModCtrl}
+	}
+	// Ctrl+\ ] ^ _ (0x1c–0x1f)
+	return KeyEvent{Key: KeyRune, Rune: unicode.ToLower(rune(b + 0x40)), Mod: ModCtrl}
+}
+
+// ParseKeyName validates a key name as written in a config file and returns
+// its canonical form (the one KeyEvent.String produces), e.g. "Ctrl+C" →
+// "ctrl+c", "shift+ctrl+up" → "ctrl+shift+up".
+func ParseKeyName(name string) (string, error) {
+	parts := strings.Split(name, "+")
+	base := parts[len(parts)-1]
+	if base == "" && len(parts) > 1 {
+		// "ctrl++" style: the key itself is "+"
+		base = "+"
+		parts = parts[:len(parts)-1]
+	}
+
+	var ev KeyEvent
+	for _, m := range parts[:len(parts)-1] {
+		switch strings.ToLower(m) {
+		case "ctrl":
+			ev.Mod |= ModCtrl
+		case "alt":
+			ev.Mod |= ModAlt
+		case "shift":
+			ev.Mod |= ModShift
+		default:
+			return "", fmt.Errorf("unknown modifier %q in key %q", m, name)
+		}
+	}
+
+	if utf8.RuneCountInString(base) == 1 {
+		r, _ := utf8.DecodeRuneInString(base)
+		if ev.Mod&ModCtrl != 0 {
+			r = unicode.ToLower(r) // terminals can't tell Ctrl+A from Ctrl+a
+		}
+		ev.Key, ev.Rune = KeyRune, r
+		return ev.String(), nil
+	}
+	lower := strings.ToLower(base)
+	if lower == "space" {
+		ev.Key, ev.Rune = KeyRune, ' '
+		return ev.String(), nil
+	}
+	for key, keyName := range keyNames {
+		if keyName == lower && key != KeyPaste && key != KeyMouse {
+			ev.Key = key
+			return ev.String(), nil
+		}
+	}
+	return "", fmt.Errorf("unknown key %q", name)
+}
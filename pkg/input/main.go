@@ -0,0 +1,346 @@
+package input
+
+import (
+	"bytes"
+	"io"
+	"strconv"
+	"strings"
+	"time"
+	"unicode/utf8"
+)
+
+// escTimeout is how long a lone ESC waits for the rest of an escape sequence
+// before it is reported as the Escape key. Terminals send a sequence in one
+// write, so anything slower was typed by a human.
+const escTimeout = 30 * time.Millisecond
+
+// pasteTimeout is how long a bracketed paste may take to end. After that
+// it is delivered as it is, so a terminal that never sends the end marker
+// doesn't swallow every key that follows.
+const pasteTimeout = time.Second
+
+var (
+	pasteStart = []byte("\x1b[200~")
+	pasteEnd   = []byte("\x1b[201~")
+)
+
+// Decoder turns raw terminal input (raw mode) into KeyEvents. It understands
+// UTF-8, C0 control keys, CSI and SS3 sequences with xterm modifiers,
+// Alt (ESC-prefixed) combos, bracketed paste and SGR mouse reports.
+type Decoder struct {
+	events chan KeyEvent
+}
+
+// NewDecoder starts decoding r in the background.
+func NewDecoder(r io.Reader) *Decoder {
+	d := &Decoder{events: make(chan KeyEvent)}
+	chunks := make(chan []byte)
+	go readChunks(r, chunks)
+	go d.run(chunks)
+	return d
+}
+
+// Events returns the decoded key events. The channel is closed when the
+// underlying reader fails or reaches EOF.
+func (d *Decoder) Events() <-chan KeyEvent {
+	return d.events
+}
+
+func readChunks(r io.Reader, chunks chan<- []byte) {
+	buf := make([]byte, 256)
+	for {
+		n, err := r.Read(buf)
+		if n > 0 {
+			chunk := make([]byte, n)
+			copy(chunk, buf[:n])
+			chunks <- chunk
+		}
+		if err != nil {
+			close(chunks)
+			return
+		}
+	}
+}
+
+func (d *Decoder) run(chunks <-chan []byte) {
+	defer close(d.events)
+
+	var pending []byte
+	var timeout <-chan time.Time
+	var pasteSince time.Time // when the paste at the start of pending began
+	for {
+		select {
+		case chunk, ok := <-chunks:
+			if !ok {
+				d.emitAll(pending, true)
+				return
+			}
+			pending = append(pending, chunk...)
+		case <-timeout:
+			pending = d.emitAll(pending, true)
+		}
+		pending = d.emitAll(pending, false)
+
+		timeout = nil
+		if !bytes.HasPrefix(pending, pasteStart) {
+			pasteSince = time.Time{}
+		} else if pasteSince.IsZero() {
+			pasteSince = time.Now()
+		}
+		if !pasteSince.IsZero() {
+			timeout = time.After(pasteTimeout - time.Since(pasteSince))
+		} else if len(pending) > 0 {
+			timeout = time.After(escTimeout)
+		}
+	}
+}
+
+// emitAll sends every complete event in buf and returns the undecoded rest.
+// With expired set, incomplete escape sequences are resolved instead of
+// waiting for more bytes (a lone ESC becomes the Escape key).
+func (d *Decoder) emitAll(buf []byte, expired bool) []byte {
+	for len(buf) > 0 {
+		ev, n := decode(buf, expired)
+		if n == 0 {
+			return buf
+		}
+		buf = buf[n:]
+		if ev != nil {
+			d.events <- *ev
+		}
+	}
+	return nil
+}
+
+// decode reads one event from the start of buf and returns it with the
+// number of bytes consumed. n == 0 means buf holds an incomplete event.
+// A nil event with n > 0 is a recognised but unsupported sequence.
+func decode(buf []byte, expired bool) (*KeyEvent, int) {
+	b := buf[0]
+	if b != 0x1b {
+		if b < 0x20 || b == 0x7f {
+			ev := ctrlKey(b)
+			return &ev, 1
+		}
+		if !utf8.FullRune(buf) {
+			if !expired {
+				return nil, 0
+			}
+			return nil, 1 // drop a truncated UTF-8 byte
+		}
+		r, size := utf8.DecodeRune(buf)
+		return &KeyEvent{Key: KeyRune, Rune: r}, size
+	}
+
+	escape := &KeyEvent{Key: KeyEscape}
+	if len(buf) == 1 {
+		if !expired {
+			return nil, 0
+		}
+		return escape, 1
+	}
+
+	switch buf[1] {
+	case '[':
+		params, final, n, complete := splitCSI(buf)
+		if !complete {
+			if !expired {
+				return nil, 0
+			}
+			return escape, 1
+		}
+		if params == "200" && final == '~' {
+			end := bytes.Index(buf[n:], pasteEnd)
+			if end < 0 {
+				if !expired {
+					return nil, 0 // a paste may span many reads; wait for its end marker
+				}
+				return &KeyEvent{Key: KeyPaste, Paste: string(buf[n:])}, len(buf)
+			}
+			return &KeyEvent{Key: KeyPaste, Paste: string(buf[n : n+end])}, n + end + len(pasteEnd)
+		}
+		return csiKey(params, final), n
+	case 'O':
+		if len(buf) < 3 {
+			if !expired {
+				return nil, 0
+			}
+			return escape, 1
+		}
+		return finalKey(buf[2], 0), 3
+	case 0x1b:
+		return escape, 1
+	default:
+		// ESC followed by a key is how terminals send Alt+key
+		ev, n := decode(buf[1:], expired)
+		if n == 0 {
+			return nil, 0
+		}
+		if ev != nil {
+			ev.Mod |= ModAlt
+		}
+		return ev, n + 1
+	}
+}
+
+// splitCSI parses "ESC [ params intermediates final" and returns the
+// parameter string, the final byte and the sequence length.
+func splitCSI(buf []byte) (params string, final byte, n int, complete bool) {
+	i := 2
+	for i < len(buf) && buf[i] >= 0x30 && buf[i] <= 0x3f {
+		i++
+	}
+	paramEnd := i
+	for i < len(buf) && buf[i] >= 0x20 && buf[i] <= 0x2f {
+		i++
+	}
+	if i >= len(buf) {
+		return "", 0, 0, false
+	}
+	if buf[i] < 0x40 || buf[i] > 0x7e {
+		// Malformed sequence: swallow the introducer and resync
+		return "", 0, 2, true
+	}
+	return string(buf[2:paramEnd]), buf[i], i + 1, true
+}
+
+// csiKey maps a CSI sequence to a key, e.g. "1;5" + 'C' is Ctrl+Right.
+func csiKey(params string, final byte) *KeyEvent {
+	if strings.HasPrefix(params, "<") && (final == 'M' || final == 'm') {
+		return sgrMouse(params[1:], final == 'm')
+	}
+
+	fields := strings.Split(params, ";")
+	num := func(i int, def int) int {
+		if i >= len(fields) {
+			return def
+		}
+		v, err := strconv.Atoi(fields[i])
+		if err != nil {
+			return def
+		}
+		return v
+	}
+	mod := modifierParam(num(1, 1))
+
+	switch final {
+	case '~':
+		key, ok := tildeKeys[num(0, 0)]
+		if !ok {
+			return nil
+		}
+		return &KeyEvent{Key: key, Mod: mod}
+	case 'Z':
+		return &KeyEvent{Key: KeyTab, Mod: ModShift}
+	case 'u':
+		// CSI <codepoint> ; <mod> u (fixterms / kitty keyboard protocol)
+		code := num(0, 0)
+		var ev KeyEvent
+		if code < 0x20 || code == 0x7f {
+			ev = ctrlKey(byte(code))
+		} else {
+			ev = KeyEvent{Key: KeyRune, Rune: rune(code)}
+		}
+		ev.Mod |= mod
+		return &ev
+	}
+	return finalKey(final, mod)
+}
+
+// sgrMouse decodes "ESC [ < b ; x ; y M" (press) / "… m" (release).
+// x and y are 1-based in the report.
+func sgrMouse(params string, release bool) *KeyEvent {
+	fields := strings.Split(params, ";")
+	if len(fields) != 3 {
+		return nil
+	}
+	var vals [3]int
+	for i, f := range fields {
+		v, err := strconv.Atoi(f)
+		if err != nil {
+			return nil
+		}
+		vals[i] = v
+	}
+	b := vals[0]
+
+	ev := &KeyEvent{Key: KeyMouse, Mouse: MouseEvent{
+		X:       vals[1] - 1,
+		Y:       vals[2] - 1,
+		Release: release,
+		Motion:  b&32 != 0,
+	}}
+	switch {
+	case b&64 != 0 && b&1 == 0:
+		ev.Mouse.Button = MouseWheelUp
+	case b&64 != 0:
+		ev.Mouse.Button = MouseWheelDown
+	default:
+		ev.Mouse.Button = MouseButton(b & 3) // 3 = no button (motion)
+	}
+	if b&4 != 0 {
+		ev.Mod |= ModShift
+	}
+	if b&8 != 0 {
+		ev.Mod |= ModAlt
+	}
+	if b&16 != 0 {
+		ev.Mod |= ModCtrl
+	}
+	return ev
+}
+
+// finalKey maps the final byte shared by CSI and SS3 cursor/function keys.
+func finalKey(final byte, mod Modifier) *KeyEvent {
+	key, ok := finalKeys[final]
+	if !ok {
+		return nil
+	}
+	return &KeyEvent{Key: key, Mod: mod}
+}
+
+var finalKeys = map[byte]Key{
+	'A': KeyUp,
+	'B': KeyDown,
+	'C': KeyRight,
+	'D': KeyLeft,
+	'H': KeyHome,
+	'F': KeyEnd,
+	'P': KeyF1,
+	'Q': KeyF2,
+	'R': KeyF3,
+	'S': KeyF4,
+}
+
+// tildeKeys maps the numeric parameter of "ESC [ n ~" sequences.
+var tildeKeys = map[int]Key{
+	1:  KeyHome,
+	2:  KeyInsert,
+	3:  KeyDelete,
+	4:  KeyEnd,
+	5:  KeyPageUp,
+	6:  KeyPageDown,
+	7:  KeyHome,
+	8:  KeyEnd,
+	11: KeyF1,
+	12: KeyF2,
+	13: KeyF3,
+	14: KeyF4,
+	15: KeyF5,
+	17: KeyF6,
+	18: KeyF7,
+	19: KeyF8,
+	20: KeyF9,
+	21: KeyF10,
+	23: KeyF11,
+	24: KeyF12,
+}
+
+// modifierParam decodes the xterm modifier parameter (1 + bitmask of
+// shift=1, alt=2, ctrl=4), which matches Modifier's bit layout.
+func modifierParam(p int) Modifier {
+	if p < 1 {
+		return 0
+	}
+	return Modifier(p-1) & (ModShift | ModAlt | ModCtrl)
+}
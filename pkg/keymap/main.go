@@ -0,0 +1,274 @@
+package keymap
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+
+	"github.com/anschnapp/pomodorofactory/pkg/config"
+	"github.com/anschnapp/pomodorofactory/pkg/input"
+)
+
+// Action is something the user can trigger with a key.
+type Action string
+
+const (
+	ActionQuit        Action = "quit"
+	ActionStart       Action = "start"
+	ActionFlow        Action = "flow"   // start an open-ended flowtime session
+	ActionFinish      Action = "finish" // end a flowtime session
+	ActionAbort       Action = "abort"
+	ActionCancel      Action = "cancel" // stop the auto-start countdown
+	ActionCelebrate   Action = "celebrate"
+	ActionPrevProduct Action = "prev"
+	ActionNextProduct Action = "next"
+	ActionPlan        Action = "plan"      // show the day's build plan
+	ActionWarehouse   Action = "warehouse" // show everything ever built
+	ActionShortBreak  Action = "short-break"
+	ActionLongBreak   Action = "long-break"
+	ActionSkipBreak   Action = "skip-break"
+	ActionExtend      Action = "extend"  // add a minute to the running timer
+	ActionShorten     Action = "shorten" // take a minute off
+	ActionCommand     Action = "command" // open the ":" command line
+	ActionHelp        Action = "help"
+)
+
+// Actions lists every action in the order help shows them.
+var Actions = []Action{
+	ActionStart, ActionFlow, ActionFinish, ActionPrevProduct, ActionNextProduct, ActionPlan, ActionWarehouse, ActionAbort, ActionCancel, ActionCelebrate,
+	ActionShortBreak, ActionLongBreak, ActionSkipBreak, ActionExtend, ActionShorten,
+	ActionCommand, ActionHelp, ActionQuit,
+}
+
+// actionWords are the command bar labels; the bound key is highlighted
+// inside the word when it appears there ("[s]tart", "e[x]it …").
+var actionWords = map[Action]string{
+	ActionQuit:        "quit",
+	ActionStart:       "start",
+	ActionFlow:        "flow",
+	ActionFinish:      "finish",
+	ActionAbort:       "exit current pomodoro",
+	ActionCancel:      "cancel auto-start",
+	ActionCelebrate:   "celebrate",
+	ActionPrevProduct: "previous product",
+	ActionNextProduct: "next product",
+	ActionPlan:        "plan",
+	ActionWarehouse:   "warehouse",
+	ActionShortBreak:  "small cooldown",
+	ActionLongBreak:   "long cooldown",
+	ActionSkipBreak:   "skip cooldown",
+	ActionExtend:      "one minute more",
+	ActionShorten:     "one minute less",
+	ActionCommand:     "command",
+	ActionHelp:        "help",
+}
+
+// Description returns the human-readable name of an action.
+func Description(a Action) string {
+	return actionWords[a]
+}
+
+// Context is the app state a binding applies in.
+type Context string
+
+const (
+	ContextGlobal      Context = "*" // every state; state-specific bindings win
+	ContextIdle        Context = "idle"
+	ContextWorking     Context = "working"
+	ContextWaiting     Context = "waiting" // pomodoro done, waiting to celebrate
+	ContextCelebrating Context = "celebrating"
+	ContextBreak       Context = "break"
+)
+
+var contexts = []Context{ContextGlobal, ContextIdle, ContextWorking, ContextWaiting, ContextCelebrating, ContextBreak}
+
+// Keymap binds key names (as produced by input.KeyEvent.String) to actions per context.
+type Keymap struct {
+	Name     string
+	bindings map[Context]map[Action][]string
+}
+
+func newKeymap(name string) *Keymap {
+	return &Keymap{Name: name, bindings: make(map[Context]map[Action][]string)}
+}
+
+// Bind replaces the keys bound to action in ctx. Passing no keys unbinds it.
+func (k *Keymap) Bind(ctx Context, action Action, keys ...string) {
+	if k.bindings[ctx] == nil {
+		k.bindings[ctx] = make(map[Action][]string)
+	}
+	k.bindings[ctx][action] = keys
+}
+
+// Action returns the action bound to key in ctx, falling back to global
+// bindings. Actions are searched in the order of Actions, so the answer
+// doesn't depend on map order.
+func (k *Keymap) Action(ctx Context, key string) (Action, bool) {
+	for _, c := range []Context{ctx, ContextGlobal} {
+		for _, action := range Actions {
+			if slices.Contains(k.bindings[c][action], key) {
+				return action, true
+			}
+		}
+	}
+	return "", false
+}
+
+// Keys returns the keys bound to action in ctx, falling back to global bindings.
+func (k *Keymap) Keys(ctx Context, action Action) []string {
+	if keys, ok := k.bindings[ctx][action]; ok {
+		return keys
+	}
+	return k.bindings[ContextGlobal][action]
+}
+
+// KeyLabel returns how the first key bound to action is shown to the user,
+// or "" if the action is unbound.
+func (k *Keymap) KeyLabel(ctx Context, action Action) string {
+	keys := k.Keys(ctx, action)
+	if len(keys) == 0 {
+		return ""
+	}
+	return DisplayKey(keys[0])
+}
+
+// Label renders an action for the command bar from its first bound key:
+// "[s]tart" when the key is a letter of the word, "[ctrl+s] start" otherwise.
+// Returns "" if the action is unbound.
+func (k *Keymap) Label(ctx Context, action Action) string {
+	keys := k.Keys(ctx, action)
+	if len(keys) == 0 {
+		return ""
+	}
+	word := actionWords[action]
+	key := keys[0]
+	if len([]rune(key)) == 1 {
+		if i := strings.Index(word, key); i >= 0 {
+			return word[:i] + "[" + key + "]" + word[i+len(key):]
+		}
+	}
+	return "[" + DisplayKey(key) + "] " + word
+}
+
+// ShortLabel is Label squeezed for a crowded command bar: modifiers in
+// Emacs notation and only the first word, "[C-s] start", "[C-g] exit".
+func (k *Keymap) ShortLabel(ctx Context, action Action) string {
+	keys := k.Keys(ctx, action)
+	if len(keys) == 0 {
+		return ""
+	}
+	word, _, _ := strings.Cut(actionWords[action], " ")
+	key := keys[0]
+	if len([]rune(key)) == 1 {
+		if i := strings.Index(word, key); i >= 0 {
+			return word[:i] + "[" + key + "]" + word[i+len(key):]
+		}
+	}
+	return "[" + shortKey(key) + "] " + word
+}
+
+var shortModifiers = strings.NewReplacer("ctrl+", "C-", "alt+", "M-", "shift+", "S-")
+
+// shortKey writes ctrl+s as C-s and alt+f as M-f.
+func shortKey(key string) string {
+	return DisplayKey(shortModifiers.Replace(key))
+}
+
+var displayKeys = map[string]string{
+	"left":  "←",
+	"right": "→",
+	"up":    "↑",
+	"down":  "↓",
+}
+
+// DisplayKey returns a compact label for a key name, e.g. "left" → "←".
+func DisplayKey(key string) string {
+	if d, ok := displayKeys[key]; ok {
+		return d
+	}
+	return key
+}
+
+// Load reads a keymap file. A missing file yields the default preset.
+//
+// The file starts from a preset and overrides single bindings, one per line:
+//
+//	# comments and blank lines are ignored
+//	preset = vim
+//	idle.start = s, enter
+//	*.quit = q, ctrl+c
+//	break.long-break =          # unbind
+func Load(path string) (*Keymap, error) {
+	settings, err := config.Load(path)
+	if err != nil {
+		return nil, err
+	}
+	if len(settings) == 0 {
+		return Default(), nil
+	}
+
+	k := Default()
+	for _, s := range settings {
+		if s.Name == "preset" {
+			preset, ok := Preset(s.Value)
+			if !ok {
+				return nil, fmt.Errorf("%s:%d: unknown preset %q", path, s.Line, s.Value)
+			}
+			k = preset
+			continue
+		}
+
+		ctxName, actionName, ok := strings.Cut(s.Name, ".")
+		if !ok {
+			return nil, fmt.Errorf("%s:%d: expected <state>.<action>, got %q", path, s.Line, s.Name)
+		}
+		ctx, err := parseContext(ctxName)
+		if err != nil {
+			return nil, fmt.Errorf("%s:%d: %w", path, s.Line, err)
+		}
+		action := Action(actionName)
+		if _, known := actionWords[action]; !known {
+			return nil, fmt.Errorf("%s:%d: unknown action %q", path, s.Line, actionName)
+		}
+		var keys []string
+		for _, name := range strings.Split(s.Value, ",") {
+			name = strings.TrimSpace(name)
+			if name == "" {
+				continue
+			}
+			key, err := input.ParseKeyName(name)
+			if err != nil {
+				return nil, fmt.Errorf("%s:%d: %w", path, s.Line, err)
+			}
+			keys = append(keys, key)
+		}
+		k.Bind(ctx, action, keys...)
+	}
+	k.Name = "custom"
+	return k, k.validate()
+}
+
+func parseContext(name string) (Context, error) {
+	for _, c := range contexts {
+		if string(c) == name {
+			return c, nil
+		}
+	}
+	return "", fmt.Errorf("unknown state %q (expected one of * idle working waiting celebrating break)", name)
+}
+
+// validate rejects a key bound to two actions in the same context.
+func (k *Keymap) validate() error {
+	for _, ctx := range contexts {
+		seen := make(map[string]Action)
+		for action, keys := range k.bindings[ctx] {
+			for _, key := range keys {
+				if other, dup := seen[key]; dup && other != action {
+					return fmt.Errorf("key %q is bound to both %q and %q in state %q", key, other, action, ctx)
+				}
+				seen[key] = action
+			}
+		}
+	}
+	return nil
+}
@@ -0,0 +1,104 @@
+package keymap
+
+// PresetNames lists the built-in keymaps.
+var PresetNames = []string{"default", "vim", "emacs"}
+
+// The presets are held to the same rules as keymap files.
+func init() {
+	for _, name := range PresetNames {
+		k, _ := Preset(name)
+		if err := k.validate(); err != nil {
+			panic("keymap preset " + name + ": " + err.Error())
+		}
+	}
+}
+
+// Preset returns a fresh copy of a built-in keymap.
+func Preset(name string) (*Keymap, bool) {
+	switch name {
+	case "default":
+		return Default(), true
+	case "vim":
+		return vim(), true
+	case "emacs":
+		return emacs(), true
+	}
+	return nil, false
+}
+
+// Default is the classic single-letter layout.
+func Default() *Keymap {
+	k := newKeymap("default")
+	k.Bind(ContextGlobal, ActionQuit, "q", "ctrl+c")
+	k.Bind(ContextGlobal, ActionCommand, ":")
+	k.Bind(ContextGlobal, ActionHelp, "?")
+	k.Bind(ContextGlobal, ActionWarehouse, "w")
+	k.Bind(ContextIdle, ActionStart, "s")
+	k.Bind(ContextIdle, ActionFlow, "f")
+	k.Bind(ContextWorking, ActionFinish, "f")
+	k.Bind(ContextIdle, ActionPrevProduct, "left", "h")
+	k.Bind(ContextIdle, ActionNextProduct, "right", "l")
+	k.Bind(ContextIdle, ActionPlan, "p")
+	k.Bind(ContextWorking, ActionAbort, "x")
+	k.Bind(ContextIdle, ActionCancel, "x")
+	k.Bind(ContextWaiting, ActionCelebrate, "c")
+	k.Bind(ContextBreak, ActionShortBreak, "s")
+	k.Bind(ContextBreak, ActionLongBreak, "l")
+	k.Bind(ContextBreak, ActionSkipBreak, "k")
+	bindTimerKeys(k)
+	return k
+}
+
+// bindTimerKeys binds +/- to lengthen and shorten the running timer.
+func bindTimerKeys(k *Keymap) {
+	for _, ctx := range []Context{ContextWorking, ContextBreak} {
+		k.Bind(ctx, ActionExtend, "+", "=")
+		k.Bind(ctx, ActionShorten, "-")
+	}
+}
+
+// vim enters "insert mode" to work and leaves it with Esc; h/l (and j/k) browse.
+func vim() *Keymap {
+	k := newKeymap("vim")
+	k.Bind(ContextGlobal, ActionQuit, "q", "ctrl+c")
+	k.Bind(ContextGlobal, ActionCommand, ":")
+	k.Bind(ContextGlobal, ActionHelp, "?")
+	k.Bind(ContextGlobal, ActionWarehouse, "w")
+	k.Bind(ContextIdle, ActionStart, "i", "enter")
+	k.Bind(ContextIdle, ActionFlow, "f")
+	k.Bind(ContextWorking, ActionFinish, "f")
+	k.Bind(ContextIdle, ActionPrevProduct, "h", "k", "left")
+	k.Bind(ContextIdle, ActionNextProduct, "l", "j", "right")
+	k.Bind(ContextIdle, ActionPlan, "p")
+	k.Bind(ContextWorking, ActionAbort, "esc")
+	k.Bind(ContextIdle, ActionCancel, "esc")
+	k.Bind(ContextWaiting, ActionCelebrate, "c", "enter")
+	k.Bind(ContextBreak, ActionShortBreak, "s")
+	k.Bind(ContextBreak, ActionLongBreak, "l")
+	k.Bind(ContextBreak, ActionSkipBreak, "esc")
+	bindTimerKeys(k)
+	return k
+}
+
+// emacs uses control chords; C-g cancels as it does everywhere in Emacs.
+func emacs() *Keymap {
+	k := newKeymap("emacs")
+	k.Bind(ContextGlobal, ActionQuit, "ctrl+x", "ctrl+c")
+	k.Bind(ContextGlobal, ActionCommand, "alt+x")
+	k.Bind(ContextGlobal, ActionHelp, "?", "f1")
+	k.Bind(ContextGlobal, ActionWarehouse, "alt+w")
+	k.Bind(ContextIdle, ActionStart, "ctrl+s", "enter")
+	k.Bind(ContextIdle, ActionFlow, "alt+f")
+	k.Bind(ContextWorking, ActionFinish, "alt+f")
+	k.Bind(ContextIdle, ActionPrevProduct, "ctrl+b", "left")
+	k.Bind(ContextIdle, ActionNextProduct, "ctrl+f", "right")
+	k.Bind(ContextIdle, ActionPlan, "alt+p")
+	k.Bind(ContextWorking, ActionAbort, "ctrl+g")
+	k.Bind(ContextIdle, ActionCancel, "ctrl+g")
+	k.Bind(ContextWaiting, ActionCelebrate, "enter", "alt+c")
+	k.Bind(ContextBreak, ActionShortBreak, "alt+s")
+	k.Bind(ContextBreak, ActionLongBreak, "alt+l")
+	k.Bind(ContextBreak, ActionSkipBreak, "ctrl+g")
+	bindTimerKeys(k)
+	return k
+}
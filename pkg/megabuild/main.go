@@ -0,0 +1,76 @@
+package megabuild
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+)
+
+// MaxSections bounds the sections a mega-build can have, far more than
+// any takes.
+const MaxSections = 100
+
+// Progress counts the finished sections of each mega-build in the works,
+// by product name.
+type Progress struct {
+	Sections map[string]int `json:"sections"`
+}
+
+// Load reads a progress file. A missing file yields no progress.
+func Load(path string) (*Progress, error) {
+	p := &Progress{Sections: make(map[string]int)}
+	data, err := os.ReadFile(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return p, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	if err := json.Unmarshal(data, p); err != nil {
+		return nil, fmt.Errorf("%s: %w", path, err)
+	}
+	if p.Sections == nil {
+		p.Sections = make(map[string]int)
+	}
+	for product, n := range p.Sections {
+		if n < 0 || n >= MaxSections {
+			return nil, fmt.Errorf("%s: %s has %d finished sections, expected 0 to %d", path, product, n, MaxSections-1)
+		}
+	}
+	return p, nil
+}
+
+// Save writes the progress, creating the directory if needed.
+func (p *Progress) Save(path string) error {
+	data, err := json.MarshalIndent(p, "", "  ")
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		return err
+	}
+	return os.WriteFile(path, append(data, '\n'), 0o644)
+}
+
+// Done returns how many sections of product are built, clamped below total.
+func (p *Progress) Done(product string, total int) int {
+	n := p.Sections[product]
+	if n >= total {
+		return total - 1
+	}
+	return n
+}
+
+// Advance records one more finished section. When that completes the
+// build it starts over and reports true.
+func (p *Progress) Advance(product string, total int) bool {
+	n := p.Done(product, total) + 1
+	if n >= total {
+		delete(p.Sections, product)
+		return true
+	}
+	p.Sections[product] = n
+	return false
+}
@@ -35,13 +35,15 @@ type Motivationcloud struct {
 	phrases    []placedPhrase
 	pendingNew *placedPhrase // new phrase waiting for the fading-out slot to clear
 	fadeOutIdx int           // index of the phrase currently fading out (-1 = none)
+	rng        *rand.Rand
 }
 
-func MakeMotivationcloud() *Motivationcloud {
+func MakeMotivationcloud(rng *rand.Rand) *Motivationcloud {
 	m := &Motivationcloud{
 		width:      cloudWidth,
 		height:     cloudHeight,
 		fadeOutIdx: -1,
+		rng:        rng,
 	}
 	m.Shuffle()
 	return m
@@ -50,10 +52,10 @@ func MakeMotivationcloud() *Motivationcloud {
 // Shuffle picks new random phrases and scatters them across the available rows.
 func (m *Motivationcloud) Shuffle() {
 	// Pick phraseCount random phrases that fit within width
-	picked := pickPhrases(phraseCount, cloudWidth-maxIndent)
+	picked := pickPhrases(m.rng, phraseCount, cloudWidth-maxIndent)
 
 	// Distribute across rows with spacing
-	m.phrases = distribute(picked, cloudHeight, cloudWidth)
+	m.phrases = distribute(m.rng, picked, cloudHeight, cloudWidth)
 
 	// All phrases start fully revealed
 	for i := range m.phrases {
@@ -85,10 +87,10 @@ func (m *Motivationcloud) ReplaceOne() {
 	if len(candidates) == 0 {
 		return
 	}
-	newText := candidates[rand.Intn(len(candidates))]
+	newText := candidates[m.rng.Intn(len(candidates))]
 
 	// Pick a random slot to replace
-	idx := rand.Intn(len(m.phrases))
+	idx := m.rng.Intn(len(m.phrases))
 	old := &m.phrases[idx]
 
 	// Prepare the new phrase on the same row
@@ -99,10 +101,10 @@ func (m *Motivationcloud) ReplaceOne() {
 	if maxInd < 0 {
 		maxInd = 0
 	}
-	col := phraseColors[rand.Intn(len(phraseColors))]
+	col := phraseColors[m.rng.Intn(len(phraseColors))]
 	pending := &placedPhrase{
 		row:         old.row,
-		indent:      rand.Intn(maxInd + 1),
+		indent:      m.rng.Intn(maxInd + 1),
 		text:        newText,
 		color:       []color.Attribute{col},
 		revealChars: 0,
@@ -147,7 +149,7 @@ func (m *Motivationcloud) Tick() bool {
 }
 
 // pickPhrases selects n unique random phrases that fit within maxLen.
-func pickPhrases(n int, maxLen int) []string {
+func pickPhrases(rng *rand.Rand, n int, maxLen int) []string {
 	// Build candidate list of phrases that fit
 	candidates := make([]string, 0, len(phrases))
 	for _, p := range phrases {
@@ -157,7 +159,7 @@ func pickPhrases(n int, maxLen int) []string {
 	}
 
 	// Shuffle and pick first n
-	rand.Shuffle(len(candidates), func(i, j int) {
+	rng.Shuffle(len(candidates), func(i, j int) {
 		candidates[i], candidates[j] = candidates[j], candidates[i]
 	})
 
@@ -169,7 +171,7 @@ func pickPhrases(n int, maxLen int) []string {
 
 // distribute places phrases on rows with at least 1 empty row between them,
 // with random indentation and color.
-func distribute(picked []string, height int, width int) []placedPhrase {
+func distribute(rng *rand.Rand, picked []string, height int, width int) []placedPhrase {
 	n := len(picked)
 	if n == 0 {
 		return nil
@@ -197,9 +199,9 @@ func distribute(picked []string, height int, width int) []placedPhrase {
 		if maxInd < 0 {
 			maxInd = 0
 		}
-		indent := rand.Intn(maxInd + 1)
+		indent := rng.Intn(maxInd + 1)
 
-		col := phraseColors[rand.Intn(len(phraseColors))]
+		col := phraseColors[rng.Intn(len(phraseColors))]
 
 		result[i] = placedPhrase{
 			row:    row,
@@ -0,0 +1,154 @@
+package plan
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+)
+
+// DateLayout is how plan dates are written.
+const DateLayout = "2006-01-02"
+
+// MaxItems is the most builds a day's plan can hold, far more than a day
+// of pomodoros.
+const MaxItems = 100
+
+// Item is one queued build.
+type Item struct {
+	Product string `json:"product"` // product name
+	Done    bool   `json:"done"`
+}
+
+// Plan is the queue of products to build on one day, plus what was built
+// that wasn't planned.
+type Plan struct {
+	Date  string   `json:"date"`
+	Items []Item   `json:"items"`
+	Extra []string `json:"extra,omitempty"`
+}
+
+// Load reads a plan file. A missing file yields an empty plan for today.
+func Load(path, today string) (*Plan, error) {
+	data, err := os.ReadFile(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return &Plan{Date: today}, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	p := &Plan{}
+	if err := json.Unmarshal(data, p); err != nil {
+		return nil, fmt.Errorf("%s: %w", path, err)
+	}
+	if len(p.Items) > MaxItems {
+		return nil, fmt.Errorf("%s: a plan holds at most %d builds, found %d", path, MaxItems, len(p.Items))
+	}
+	return p, nil
+}
+
+// Save writes the plan, creating the directory if needed.
+func (p *Plan) Save(path string) error {
+	data, err := json.MarshalIndent(p, "", "  ")
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		return err
+	}
+	return os.WriteFile(path, append(data, '\n'), 0o644)
+}
+
+// Rollover starts a fresh plan when the day has changed. It returns the
+// finished day's plan if it had anything in it, for a summary.
+func (p *Plan) Rollover(today string) *Plan {
+	if p.Date == today {
+		return nil
+	}
+	finished := *p
+	*p = Plan{Date: today}
+	if len(finished.Items) == 0 && len(finished.Extra) == 0 {
+		return nil
+	}
+	return &finished
+}
+
+// Add queues count builds of product at the end of the plan.
+func (p *Plan) Add(product string, count int) error {
+	if count > MaxItems-len(p.Items) {
+		return fmt.Errorf("a plan holds at most %d builds", MaxItems)
+	}
+	for i := 0; i < count; i++ {
+		p.Items = append(p.Items, Item{Product: product})
+	}
+	return nil
+}
+
+// Remove drops the item at 1-based position n.
+func (p *Plan) Remove(n int) error {
+	if n < 1 || n > len(p.Items) {
+		return fmt.Errorf("no item %d in the plan", n)
+	}
+	p.Items = append(p.Items[:n-1], p.Items[n:]...)
+	return nil
+}
+
+// Move puts the item at 1-based position from at position to.
+func (p *Plan) Move(from, to int) error {
+	if from < 1 || from > len(p.Items) {
+		return fmt.Errorf("no item %d in the plan", from)
+	}
+	if to < 1 || to > len(p.Items) {
+		return fmt.Errorf("no item %d in the plan", to)
+	}
+	item := p.Items[from-1]
+	p.Items = append(p.Items[:from-1], p.Items[from:]...)
+	p.Items = append(p.Items[:to-1], append([]Item{item}, p.Items[to-1:]...)...)
+	return nil
+}
+
+// Clear drops the builds still to do; what was built today stays in the
+// plan as done.
+func (p *Plan) Clear() {
+	done := p.Items[:0]
+	for _, item := range p.Items {
+		if item.Done {
+			done = append(done, item)
+		}
+	}
+	p.Items = done
+}
+
+// Next returns the product of the first item not built yet.
+func (p *Plan) Next() (string, bool) {
+	for _, item := range p.Items {
+		if !item.Done {
+			return item.Product, true
+		}
+	}
+	return "", false
+}
+
+// Record marks the first open item for product as built, or notes the
+// build as extra when it wasn't planned. Reports whether it was planned.
+func (p *Plan) Record(product string) bool {
+	for i, item := range p.Items {
+		if !item.Done && item.Product == product {
+			p.Items[i].Done = true
+			return true
+		}
+	}
+	p.Extra = append(p.Extra, product)
+	return false
+}
+
+// Progress returns how many planned items are built and how many are planned.
+func (p *Plan) Progress() (done, total int) {
+	for _, item := range p.Items {
+		if item.Done {
+			done++
+		}
+	}
+	return done, len(p.Items)
+}
@@ -0,0 +1,187 @@
+package product
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"unicode"
+
+	"github.com/anschnapp/pomodorofactory/pkg/audio"
+	"github.com/anschnapp/pomodorofactory/pkg/runecolor"
+	"github.com/fatih/color"
+)
+
+// Custom is a product kept as a file in the products folder, made by the
+// image importer or the art editor instead of being compiled in.
+type Custom struct {
+	Name  string `json:"name"`
+	Emoji string `json:"emoji"`
+	Voice string `json:"voice,omitempty"`
+	Style string `json:"style,omitempty"`
+	// Art is the picture, one string per row. Colors has one string per
+	// row too: an RRGGBB hex color per cell, space-separated, or "-" for
+	// the terminal's default color.
+	Art    []string `json:"art"`
+	Colors []string `json:"colors"`
+	// Celebration is the product's own party, written by hand.
+	Celebration *CustomCelebration `json:"celebration,omitempty"`
+}
+
+// CustomCelebration is Celebration as a product file spells it: colors in
+// RRGGBB and the spark glyphs as one string.
+type CustomCelebration struct {
+	Colors []string `json:"colors,omitempty"`
+	Sparks string   `json:"sparks,omitempty"`
+	Jingle string   `json:"jingle,omitempty"`
+	Pitch  float64  `json:"pitch,omitempty"`
+	Cheers []string `json:"cheers,omitempty"`
+	Verbs  []string `json:"verbs,omitempty"`
+}
+
+// NoColor marks a cell drawn in the terminal's default color.
+const NoColor = "-"
+
+// Product turns the file's art and colors into a buildable product.
+func (c *Custom) Product() (*Product, error) {
+	if c.Name == "" {
+		return nil, errors.New("no name")
+	}
+	art := make([][]runecolor.ColoredRune, len(c.Art))
+	for i, line := range c.Art {
+		var colors []string
+		if i < len(c.Colors) {
+			colors = strings.Fields(c.Colors[i])
+		}
+		runes := []rune(line)
+		art[i] = make([]runecolor.ColoredRune, len(runes))
+		for j, r := range runes {
+			art[i][j] = runecolor.ColoredRune{Symbol: r}
+			if j >= len(colors) || colors[j] == NoColor {
+				continue
+			}
+			attrs, err := ParseHexColor(colors[j])
+			if err != nil {
+				return nil, fmt.Errorf("row %d, column %d: %w", i+1, j+1, err)
+			}
+			art[i][j].ColorAttributes = attrs
+		}
+	}
+	emoji := c.Emoji
+	if emoji == "" {
+		emoji = "📦"
+	}
+	p := &Product{Name: c.Name, Emoji: emoji, Art: art, Voice: c.Voice, Style: c.Style}
+	if cc := c.Celebration; cc != nil {
+		p.Celebration = Celebration{Sparks: []rune(strings.ReplaceAll(cc.Sparks, " ", "")), Jingle: cc.Jingle, Pitch: cc.Pitch, Cheers: cc.Cheers, Verbs: cc.Verbs}
+		if cc.Jingle != "" {
+			if _, err := audio.ParseJingle(cc.Jingle); err != nil {
+				return nil, fmt.Errorf("celebration: %w", err)
+			}
+		}
+		for _, hex := range cc.Colors {
+			attrs, err := ParseHexColor(hex)
+			if err != nil {
+				return nil, fmt.Errorf("celebration: %w", err)
+			}
+			p.Celebration.Colors = append(p.Celebration.Colors, attrs)
+		}
+	}
+	return p, nil
+}
+
+// TrueColor is the foreground attribute sequence for an RGB color.
+func TrueColor(r, g, b uint8) []color.Attribute {
+	return []color.Attribute{38, 2, color.Attribute(r), color.Attribute(g), color.Attribute(b)}
+}
+
+// HexColor formats an RGB color the way Custom.Colors stores it.
+func HexColor(r, g, b uint8) string {
+	return fmt.Sprintf("%02x%02x%02x", r, g, b)
+}
+
+// ParseHexColor turns an RRGGBB color into its foreground attributes.
+func ParseHexColor(s string) ([]color.Attribute, error) {
+	v, err := strconv.ParseUint(s, 16, 32)
+	if err != nil || len(s) != 6 {
+		return nil, fmt.Errorf("bad color %q (expected RRGGBB)", s)
+	}
+	return TrueColor(uint8(v>>16), uint8(v>>8), uint8(v)), nil
+}
+
+// LoadCustom reads every product file in dir, sorted by file name. A
+// missing folder yields no products.
+func LoadCustom(dir string) ([]*Product, error) {
+	paths, err := filepath.Glob(filepath.Join(dir, "*.json"))
+	if err != nil {
+		return nil, err
+	}
+	sort.Strings(paths)
+	var products []*Product
+	for _, path := range paths {
+		c, err := ReadCustom(path)
+		if err != nil {
+			return nil, err
+		}
+		p, err := c.Product()
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", path, err)
+		}
+		products = append(products, p)
+	}
+	return products, nil
+}
+
+// ReadCustom reads one product file.
+func ReadCustom(path string) (*Custom, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	c := &Custom{}
+	if err := json.Unmarshal(data, c); err != nil {
+		return nil, fmt.Errorf("%s: %w", path, err)
+	}
+	return c, nil
+}
+
+// Save writes the product to dir as <slug>.json, creating dir if needed,
+// and returns the path written.
+func (c *Custom) Save(dir string) (string, error) {
+	data, err := json.MarshalIndent(c, "", "  ")
+	if err != nil {
+		return "", err
+	}
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return "", err
+	}
+	path := filepath.Join(dir, Slug(c.Name)+".json")
+	return path, os.WriteFile(path, append(data, '\n'), 0o644)
+}
+
+// Slug turns a product name into a file name: "Raspberry on Ice" becomes
+// "raspberry-on-ice".
+func Slug(name string) string {
+	var b strings.Builder
+	dash := false
+	for _, r := range strings.ToLower(name) {
+		switch {
+		case unicode.IsLetter(r) || unicode.IsDigit(r):
+			if dash && b.Len() > 0 {
+				b.WriteByte('-')
+			}
+			b.WriteRune(r)
+			dash = false
+		default:
+			dash = true
+		}
+	}
+	if b.Len() == 0 {
+		return "product"
+	}
+	return b.String()
+}
@@ -1,9 +1,41 @@
 package product
 
-import "github.com/anschnapp/pomodorofactory/pkg/runecolor"
+import (
+	"github.com/anschnapp/pomodorofactory/pkg/runecolor"
+	"github.com/fatih/color"
+)
 
 type Product struct {
 	Name  string
 	Emoji string
 	Art   [][]runecolor.ColoredRune // pre-colored, ready for factoryscene
+	Voice string                    // audio voice profile for the celebration speech; "" = default
+	Style string                    // build animation, see factoryscene.Styles; "" = crane
+	// Frames, if set, are cycled once the product is built and during the
+	// break; the first is usually Art itself.
+	Frames [][][]runecolor.ColoredRune
+	// Sessions is how many pomodoros a mega-build takes, each welding a
+	// slice of rows; 0 or 1 is a regular product.
+	Sessions int
+	// ColorMap is the scheme the art was colored with, kept for linting;
+	// nil for products colored cell by cell.
+	ColorMap map[rune][]color.Attribute
+	// Celebration is how the product's own party looks and sounds; zero
+	// fields keep the factory's defaults.
+	Celebration Celebration
+}
+
+// Celebration customizes the party after a product is built.
+type Celebration struct {
+	Colors [][]color.Attribute // spark colors
+	Sparks []rune              // spark glyphs
+	Jingle string              // fanfare in audio's jingle notation
+	Pitch  float64             // multiplier on the voice's pitch
+	Cheers []string            // "Bravo", opening the message
+	Verbs  []string            // "brewed", as in "we proudly brewed a coffee"
+}
+
+// IsMega reports whether the product takes more than one pomodoro.
+func (p *Product) IsMega() bool {
+	return p.Sessions > 1
 }
@@ -14,18 +14,51 @@ var tomatoAsciiStr string
 //go:embed art/coffee.txt
 var coffeeAsciiStr string
 
+//go:embed art/coffee-1.txt
+var coffeeSteam1AsciiStr string
+
+//go:embed art/coffee-2.txt
+var coffeeSteam2AsciiStr string
+
 //go:embed art/penguin.txt
 var penguinAsciiStr string
 
+//go:embed art/penguin-1.txt
+var penguinWaddleAsciiStr string
+
 //go:embed art/orange.txt
 var oragngeAsciiStr string
 
 //go:embed art/eifeltower.txt
 var eifelTowerAsciiStr string
 
+//go:embed art/eifeltower-1.txt
+var eifelTowerSparkle1AsciiStr string
+
+//go:embed art/eifeltower-2.txt
+var eifelTowerSparkle2AsciiStr string
+
 //go:embed art/raspberry.txt
 var raspberryAsciiStr string
 
+//go:embed art/rocket.txt
+var rocketAsciiStr string
+
+//go:embed art/cathedral.txt
+var cathedralAsciiStr string
+
+//go:embed art/skyline.txt
+var skylineAsciiStr string
+
+//go:embed art/donut.txt
+var donutAsciiStr string
+
+//go:embed art/robot.txt
+var robotAsciiStr string
+
+//go:embed art/trophy.txt
+var trophyAsciiStr string
+
 // All is the ordered list of buildable products.
 var All []*Product
 
@@ -37,6 +70,12 @@ func init() {
 		makeOrange(),
 		makeEifenTower(),
 		makeRaspberry(),
+		makeRocket(),
+		makeCathedral(),
+		makeSkyline(),
+		makeDonut(),
+		makeRobot(),
+		makeTrophy(),
 	}
 }
 
@@ -52,7 +91,7 @@ func makeTomato() *Product {
 	for i, row := range rows {
 		art[i] = runecolor.ConvertRunesToColoredRunes(row, colorMap, defaultColor)
 	}
-	return &Product{Name: "Tomato", Emoji: "🍅", Art: art}
+	return &Product{Name: "Tomato", Emoji: "🍅", Art: art, ColorMap: colorMap}
 }
 
 func makeCoffee() *Product {
@@ -69,7 +108,20 @@ func makeCoffee() *Product {
 	for i, row := range rows {
 		art[i] = runecolor.ConvertRunesToColoredRunes(row, colorMap, defaultColor)
 	}
-	return &Product{Name: "Coffee Cup", Emoji: "☕", Art: art}
+	frames := colorFrames(colorMap, defaultColor, coffeeAsciiStr, coffeeSteam1AsciiStr, coffeeSteam2AsciiStr)
+	// Bubbles and steam in coffee colors, and a percolator gurgle
+	celebration := Celebration{
+		Colors: [][]color.Attribute{
+			{38, 2, 139, 90, 43},   // RGB coffee brown
+			{38, 2, 220, 190, 140}, // RGB crema
+			{color.FgHiWhite},
+		},
+		Sparks: []rune{'~', '°', 'o', '∘'},
+		Jingle: "tempo=180 adsr=5,60,0.4,30 vol=0.3 gap=20 sine:G5/16 C6/16 G5/16 C6/16 E6/8 rest/16 C6/8",
+		Pitch:  1.1,
+		Verbs:  []string{"brewed", "poured", "roasted", "percolated", "steamed", "frothed", "ground", "filtered"},
+	}
+	return &Product{Name: "Coffee Cup", Emoji: "☕", Art: art, Frames: frames, Voice: "intern", Style: "pour", ColorMap: colorMap, Celebration: celebration}
 }
 func makeOrange() *Product {
 	rows := iohelper.SplitMultilineStringToSlice(oragngeAsciiStr)
@@ -82,7 +134,7 @@ func makeOrange() *Product {
 	for i, row := range rows {
 		art[i] = runecolor.ConvertRunesToColoredRunes(row, colorMap, defaultColor)
 	}
-	return &Product{Name: "Orange", Emoji: "🍊", Art: art}
+	return &Product{Name: "Orange", Emoji: "🍊", Art: art, ColorMap: colorMap}
 }
 
 func makeEifenTower() *Product {
@@ -92,12 +144,27 @@ func makeEifenTower() *Product {
 	colorMap['8'] = []color.Attribute{38, 2, 155, 125, 60} // RGB medium iron (crossbeam center)
 	colorMap['9'] = []color.Attribute{38, 2, 80, 60, 20}   // RGB dark iron (right side)
 	defaultColor := []color.Attribute{38, 2, 220, 190, 110}
+	// Night lights, only in the animation frames
+	colorMap['*'] = runecolor.MakeSingleColorAttributes(color.FgHiYellow)
 
 	art := make([][]runecolor.ColoredRune, len(rows))
 	for i, row := range rows {
 		art[i] = runecolor.ConvertRunesToColoredRunes(row, colorMap, defaultColor)
 	}
-	return &Product{Name: "Eifeltower", Emoji: "🗼", Art: art}
+	frames := colorFrames(colorMap, defaultColor, eifelTowerAsciiStr, eifelTowerSparkle1AsciiStr, eifelTowerAsciiStr, eifelTowerSparkle2AsciiStr)
+	// Tricolore sparks, French cheers and the opening of the Marseillaise
+	celebration := Celebration{
+		Colors: [][]color.Attribute{
+			{38, 2, 0, 85, 164},  // RGB bleu
+			{color.FgHiWhite},    // blanc
+			{38, 2, 239, 65, 53}, // RGB rouge
+		},
+		Sparks: []rune{'*', '✦', '+'},
+		Jingle: "tempo=160 adsr=8,0,1,20 vol=0.25 gap=20 square:D4/16 D4/8. D4/16 G4/4 G4/4 A4/4 A4/4 D5/4. B4/8 G4/4",
+		Pitch:  0.9,
+		Cheers: []string{"Magnifique", "Formidable", "Bravo", "Chapeau", "Superbe", "Épatant", "Voilà", "Ooh là là"},
+	}
+	return &Product{Name: "Eifeltower", Emoji: "🗼", Art: art, Frames: frames, Voice: "foreman", ColorMap: colorMap, Celebration: celebration}
 }
 
 func makeRaspberry() *Product {
@@ -114,7 +181,7 @@ func makeRaspberry() *Product {
 	for i, row := range rows {
 		art[i] = runecolor.ConvertRunesToColoredRunes(row, colorMap, defaultColor)
 	}
-	return &Product{Name: "Raspberry on Ice", Emoji: "🍧", Art: art}
+	return &Product{Name: "Raspberry on Ice", Emoji: "🍧", Art: art, Voice: "intern", ColorMap: colorMap}
 }
 
 func makePenguin() *Product {
@@ -133,5 +200,121 @@ func makePenguin() *Product {
 	for i, row := range rows {
 		art[i] = runecolor.ConvertRunesToColoredRunes(row, colorMap, defaultColor)
 	}
-	return &Product{Name: "Penguin", Emoji: "🐧", Art: art}
+	frames := colorFrames(colorMap, defaultColor, penguinAsciiStr, penguinWaddleAsciiStr)
+	return &Product{Name: "Penguin", Emoji: "🐧", Art: art, Frames: frames, Voice: "robot", ColorMap: colorMap}
+}
+
+func makeRocket() *Product {
+	rows := iohelper.SplitMultilineStringToSlice(rocketAsciiStr)
+	colorMap := make(map[rune][]color.Attribute)
+	colorMap['('] = runecolor.MakeSingleColorAttributes(color.FgHiCyan) // portholes
+	colorMap[')'] = runecolor.MakeSingleColorAttributes(color.FgHiCyan)
+	colorMap['#'] = runecolor.MakeSingleColorAttributes(color.FgHiRed)
+	colorMap['^'] = runecolor.MakeSingleColorAttributes(color.FgHiYellow) // exhaust
+	colorMap['='] = runecolor.MakeSingleColorAttributes(color.FgHiBlack)  // launch pad
+	defaultColor := runecolor.MakeSingleColorAttributes(color.FgHiWhite)
+
+	art := make([][]runecolor.ColoredRune, len(rows))
+	for i, row := range rows {
+		art[i] = runecolor.ConvertRunesToColoredRunes(row, colorMap, defaultColor)
+	}
+	// Exhaust flames and a countdown blast-off
+	celebration := Celebration{
+		Colors: [][]color.Attribute{
+			{color.FgHiYellow},
+			{color.FgHiRed},
+			{38, 2, 255, 140, 0}, // RGB flame orange
+		},
+		Sparks: []rune{'^', '*', '\'', '.'},
+		Jingle: "tempo=150 adsr=5,0,1,20 vol=0.25 gap=40 square:C5/8 C5/8 C5/8 saw:C4/16 E4/16 G4/16 C5/16 E5/16 G5/16 C6/4",
+		Verbs:  []string{"launched", "fueled", "riveted", "fired up", "rolled out", "assembled"},
+	}
+	return &Product{Name: "Rocket", Emoji: "🚀", Art: art, Voice: "robot", Sessions: 3, ColorMap: colorMap, Celebration: celebration}
+}
+
+func makeCathedral() *Product {
+	rows := iohelper.SplitMultilineStringToSlice(cathedralAsciiStr)
+	colorMap := make(map[rune][]color.Attribute)
+	colorMap['+'] = runecolor.MakeSingleColorAttributes(color.FgHiYellow)
+	colorMap['#'] = []color.Attribute{38, 2, 100, 149, 237}                // stained glass
+	colorMap['o'] = runecolor.MakeSingleColorAttributes(color.FgHiMagenta) // rose windows
+	colorMap['('] = runecolor.MakeSingleColorAttributes(color.FgHiMagenta)
+	colorMap[')'] = runecolor.MakeSingleColorAttributes(color.FgHiMagenta)
+	defaultColor := []color.Attribute{38, 2, 200, 190, 170} // sandstone
+
+	art := make([][]runecolor.ColoredRune, len(rows))
+	for i, row := range rows {
+		art[i] = runecolor.ConvertRunesToColoredRunes(row, colorMap, defaultColor)
+	}
+	return &Product{Name: "Cathedral", Emoji: "⛪", Art: art, Voice: "foreman", Sessions: 4, ColorMap: colorMap}
+}
+
+func makeSkyline() *Product {
+	rows := iohelper.SplitMultilineStringToSlice(skylineAsciiStr)
+	colorMap := make(map[rune][]color.Attribute)
+	colorMap['#'] = runecolor.MakeSingleColorAttributes(color.FgHiYellow) // lit windows
+	colorMap['='] = runecolor.MakeSingleColorAttributes(color.FgHiBlack)  // street
+	defaultColor := []color.Attribute{38, 2, 120, 140, 180}               // steel blue
+
+	art := make([][]runecolor.ColoredRune, len(rows))
+	for i, row := range rows {
+		art[i] = runecolor.ConvertRunesToColoredRunes(row, colorMap, defaultColor)
+	}
+	return &Product{Name: "Skyline", Emoji: "🌆", Art: art, Style: "printer", Sessions: 3, ColorMap: colorMap}
+}
+
+func makeDonut() *Product {
+	rows := iohelper.SplitMultilineStringToSlice(donutAsciiStr)
+	colorMap := make(map[rune][]color.Attribute)
+	colorMap[':'] = []color.Attribute{38, 2, 255, 130, 190}             // RGB pink frosting
+	colorMap['*'] = runecolor.MakeSingleColorAttributes(color.FgHiCyan) // sprinkles
+	defaultColor := []color.Attribute{38, 2, 205, 145, 75}              // RGB dough
+
+	art := make([][]runecolor.ColoredRune, len(rows))
+	for i, row := range rows {
+		art[i] = runecolor.ConvertRunesToColoredRunes(row, colorMap, defaultColor)
+	}
+	return &Product{Name: "Donut", Emoji: "🍩", Art: art, Voice: "intern", ColorMap: colorMap}
+}
+
+func makeRobot() *Product {
+	rows := iohelper.SplitMultilineStringToSlice(robotAsciiStr)
+	colorMap := make(map[rune][]color.Attribute)
+	colorMap['#'] = runecolor.MakeSingleColorAttributes(color.FgHiRed)  // antenna light
+	colorMap['o'] = runecolor.MakeSingleColorAttributes(color.FgHiCyan) // eyes
+	colorMap['='] = runecolor.MakeSingleColorAttributes(color.FgHiYellow)
+	defaultColor := []color.Attribute{38, 2, 180, 190, 200} // RGB brushed steel
+
+	art := make([][]runecolor.ColoredRune, len(rows))
+	for i, row := range rows {
+		art[i] = runecolor.ConvertRunesToColoredRunes(row, colorMap, defaultColor)
+	}
+	return &Product{Name: "Robot", Emoji: "🤖", Art: art, Voice: "robot", Style: "printer", ColorMap: colorMap}
+}
+
+func makeTrophy() *Product {
+	rows := iohelper.SplitMultilineStringToSlice(trophyAsciiStr)
+	colorMap := make(map[rune][]color.Attribute)
+	colorMap[':'] = runecolor.MakeSingleColorAttributes(color.FgHiWhite) // shine
+	colorMap['='] = []color.Attribute{38, 2, 180, 130, 20}               // RGB dark gold
+	defaultColor := []color.Attribute{38, 2, 255, 200, 50}               // RGB gold
+
+	art := make([][]runecolor.ColoredRune, len(rows))
+	for i, row := range rows {
+		art[i] = runecolor.ConvertRunesToColoredRunes(row, colorMap, defaultColor)
+	}
+	return &Product{Name: "Trophy", Emoji: "🏆", Art: art, Voice: "foreman", Style: "fill", ColorMap: colorMap}
+}
+
+// colorFrames loads animation frames with the same color scheme as the art.
+func colorFrames(colorMap map[rune][]color.Attribute, defaultColor []color.Attribute, asciiStrs ...string) [][][]runecolor.ColoredRune {
+	frames := make([][][]runecolor.ColoredRune, len(asciiStrs))
+	for f, asciiStr := range asciiStrs {
+		rows := iohelper.SplitMultilineStringToSlice(asciiStr)
+		frames[f] = make([][]runecolor.ColoredRune, len(rows))
+		for i, row := range rows {
+			frames[f][i] = runecolor.ConvertRunesToColoredRunes(row, colorMap, defaultColor)
+		}
+	}
+	return frames
 }
@@ -0,0 +1,151 @@
+package progression
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"slices"
+	"time"
+)
+
+// Counters are the lifetime totals that unlock products and upgrades.
+type Counters struct {
+	Built   map[string]int `json:"built"`   // finished products by name
+	Days    []string       `json:"days"`    // days with a finished pomodoro, oldest first
+	Focused time.Duration  `json:"focused"` // total time worked
+}
+
+// Load reads a counters file. A missing file yields zero counters and
+// false, so the caller can seed them from older records.
+func Load(path string) (*Counters, bool, error) {
+	c := &Counters{Built: make(map[string]int)}
+	data, err := os.ReadFile(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return c, false, nil
+	}
+	if err != nil {
+		return nil, false, err
+	}
+	if err := json.Unmarshal(data, c); err != nil {
+		return nil, false, fmt.Errorf("%s: %w", path, err)
+	}
+	if c.Built == nil {
+		c.Built = make(map[string]int)
+	}
+	return c, true, nil
+}
+
+// Save writes the counters, creating the directory if needed.
+func (c *Counters) Save(path string) error {
+	data, err := json.MarshalIndent(c, "", "  ")
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		return err
+	}
+	return os.WriteFile(path, append(data, '\n'), 0o644)
+}
+
+// Focus counts a finished pomodoro of length d worked on day.
+func (c *Counters) Focus(d time.Duration, day string) {
+	c.Focused += d
+	if !slices.Contains(c.Days, day) {
+		c.Days = append(c.Days, day)
+	}
+}
+
+// Build counts a finished product.
+func (c *Counters) Build(product string) {
+	c.Built[product]++
+}
+
+// Upgrade is a cosmetic improvement of the factory.
+type Upgrade string
+
+const (
+	BrightSparks Upgrade = "bright sparks"
+	SecondArm    Upgrade = "second crane arm"
+	SteelBorder  Upgrade = "steel blue walls"
+	GoldenPillar Upgrade = "golden pillar"
+)
+
+// Milestone unlocks products and upgrades once a counter reaches its goal.
+type Milestone struct {
+	Name     string // e.g. "10 tomatoes"
+	Goal     int
+	Products []string
+	Upgrades []Upgrade
+	count    func(c *Counters) int
+}
+
+// Milestones are reached in any order; products not listed are unlocked
+// from the start. Only products made to be earned are listed, so nobody
+// loses one they could build before.
+var Milestones = []Milestone{
+	{
+		Name:     "10 tomatoes",
+		Goal:     10,
+		Products: []string{"Donut"},
+		Upgrades: []Upgrade{BrightSparks},
+		count:    func(c *Counters) int { return c.Built["Tomato"] },
+	},
+	{
+		Name:     "5 active days",
+		Goal:     5,
+		Products: []string{"Robot"},
+		Upgrades: []Upgrade{SecondArm, SteelBorder},
+		count:    func(c *Counters) int { return len(c.Days) },
+	},
+	{
+		Name:     "100 focused hours",
+		Goal:     100,
+		Products: []string{"Trophy"},
+		Upgrades: []Upgrade{GoldenPillar},
+		count:    func(c *Counters) int { return int(c.Focused.Hours()) },
+	},
+}
+
+// Progress returns how far the counters are towards m, capped at the goal.
+func (m Milestone) Progress(c *Counters) int {
+	return min(m.count(c), m.Goal)
+}
+
+// Reached reports whether the counters meet m's goal.
+func (m Milestone) Reached(c *Counters) bool {
+	return m.count(c) >= m.Goal
+}
+
+// Reached lists the milestones met so far.
+func (c *Counters) Reached() []Milestone {
+	var reached []Milestone
+	for _, m := range Milestones {
+		if m.Reached(c) {
+			reached = append(reached, m)
+		}
+	}
+	return reached
+}
+
+// LockedBy returns the milestone product is waiting for, or false if it
+// can be built.
+func (c *Counters) LockedBy(product string) (Milestone, bool) {
+	for _, m := range Milestones {
+		if slices.Contains(m.Products, product) && !m.Reached(c) {
+			return m, true
+		}
+	}
+	return Milestone{}, false
+}
+
+// Has reports whether upgrade u has been earned.
+func (c *Counters) Has(u Upgrade) bool {
+	for _, m := range c.Reached() {
+		if slices.Contains(m.Upgrades, u) {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,32 @@
+package runecolor
+
+import (
+	"strconv"
+	"strings"
+)
+
+// ANSI renders a line with SGR escape sequences, for printing outside the
+// canvas. Zero-width sentinels are skipped as in View.Print.
+func ANSI(line []ColoredRune) string {
+	var buf strings.Builder
+	for _, r := range line {
+		if r.Symbol == 0 {
+			continue
+		}
+		if len(r.ColorAttributes) > 0 {
+			buf.WriteString("\033[")
+			for i, attr := range r.ColorAttributes {
+				if i > 0 {
+					buf.WriteByte(';')
+				}
+				buf.WriteString(strconv.Itoa(int(attr)))
+			}
+			buf.WriteByte('m')
+		}
+		buf.WriteRune(r.Symbol)
+		if len(r.ColorAttributes) > 0 {
+			buf.WriteString("\033[0m")
+		}
+	}
+	return buf.String()
+}
@@ -0,0 +1,187 @@
+package schedule
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/anschnapp/pomodorofactory/pkg/config"
+)
+
+// Kind tells work steps from breaks.
+type Kind int
+
+const (
+	Work Kind = iota
+	Break
+)
+
+func (k Kind) String() string {
+	if k == Break {
+		return "break"
+	}
+	return "work"
+}
+
+// Step is one timed block of a schedule.
+type Step struct {
+	Kind     Kind
+	Duration time.Duration
+}
+
+// Schedule is a named cycle of alternating work and break steps, starting
+// with work. After the last step it starts over.
+type Schedule struct {
+	Name  string
+	Steps []Step
+}
+
+// Builtin schedules, by name.
+var builtins = map[string]string{
+	"classic":   "work 25, break 5, work 25, break 5, work 25, break 5, work 25, break 15",
+	"deep":      "work 50, break 10, work 50, break 10, work 50, break 30",
+	"ultradian": "work 90, break 20",
+}
+
+// DefaultName is the schedule used unless another one is picked.
+const DefaultName = "classic"
+
+// Names lists the builtin schedule names.
+func Names() []string {
+	names := make([]string, 0, len(builtins))
+	for name := range builtins {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// Parse reads a schedule spec such as "work 50, break 10, work 50, break 30".
+// Durations are minutes, decimals allowed.
+func Parse(name, spec string) (*Schedule, error) {
+	s := &Schedule{Name: name}
+	for _, part := range strings.Split(spec, ",") {
+		fields := strings.Fields(part)
+		if len(fields) != 2 {
+			return nil, fmt.Errorf("expected \"work <minutes>\" or \"break <minutes>\", got %q", strings.TrimSpace(part))
+		}
+		var kind Kind
+		switch fields[0] {
+		case "work":
+			kind = Work
+		case "break":
+			kind = Break
+		default:
+			return nil, fmt.Errorf("unknown step %q (expected work or break)", fields[0])
+		}
+		minutes, err := strconv.ParseFloat(fields[1], 64)
+		if err != nil || minutes <= 0 {
+			return nil, fmt.Errorf("invalid minutes %q", fields[1])
+		}
+		s.Steps = append(s.Steps, Step{Kind: kind, Duration: time.Duration(minutes * float64(time.Minute))})
+	}
+	for i, step := range s.Steps {
+		if want := Kind(i % 2); step.Kind != want {
+			return nil, fmt.Errorf("step %d is %s, but steps must alternate work and break, starting with work", i+1, step.Kind)
+		}
+	}
+	if len(s.Steps)%2 != 0 {
+		return nil, errors.New("a schedule must end with a break")
+	}
+	return s, nil
+}
+
+// Lookup resolves a schedule by name: a builtin, one defined in the user's
+// schedules file (which may be missing), or else an inline spec.
+//
+// The schedules file has one schedule per line:
+//
+//	# comments and blank lines are ignored
+//	sprint = work 15, break 3
+func Lookup(name, userFile string) (*Schedule, error) {
+	user, err := loadFile(userFile)
+	if err != nil {
+		return nil, err
+	}
+	if spec, ok := user[name]; ok {
+		return Parse(name, spec)
+	}
+	if spec, ok := builtins[name]; ok {
+		return Parse(name, spec)
+	}
+	if strings.Contains(name, "work") {
+		return Parse("custom", name)
+	}
+	return nil, fmt.Errorf("unknown schedule %q (builtin: %s)", name, strings.Join(Names(), ", "))
+}
+
+func loadFile(path string) (map[string]string, error) {
+	settings, err := config.Load(path)
+	if err != nil {
+		return nil, err
+	}
+	specs := make(map[string]string)
+	for _, set := range settings {
+		if _, err := Parse(set.Name, set.Value); err != nil {
+			return nil, fmt.Errorf("%s:%d: %w", path, set.Line, err)
+		}
+		specs[set.Name] = set.Value
+	}
+	return specs, nil
+}
+
+// WithWork returns a copy where every work step lasts d.
+func (s *Schedule) WithWork(d time.Duration) *Schedule {
+	c := &Schedule{Name: s.Name, Steps: make([]Step, len(s.Steps))}
+	for i, step := range s.Steps {
+		if step.Kind == Work {
+			step.Duration = d
+		}
+		c.Steps[i] = step
+	}
+	return c
+}
+
+// Next returns the index of the step after i.
+func (s *Schedule) Next(i int) int {
+	return (i + 1) % len(s.Steps)
+}
+
+// Position returns which work block step i is (a break counts with the work
+// before it), 1-based, and how many there are in the cycle.
+func (s *Schedule) Position(i int) (n, total int) {
+	return i/2 + 1, len(s.Steps) / 2
+}
+
+// ShortBreak and LongBreak are the shortest and longest breaks in the cycle.
+func (s *Schedule) ShortBreak() time.Duration {
+	var d time.Duration
+	for _, step := range s.Steps {
+		if step.Kind == Break && (d == 0 || step.Duration < d) {
+			d = step.Duration
+		}
+	}
+	return d
+}
+
+func (s *Schedule) LongBreak() time.Duration {
+	var d time.Duration
+	for _, step := range s.Steps {
+		if step.Kind == Break && step.Duration > d {
+			d = step.Duration
+		}
+	}
+	return d
+}
+
+// String renders the steps in the same form Parse reads.
+func (s *Schedule) String() string {
+	parts := make([]string, len(s.Steps))
+	for i, step := range s.Steps {
+		parts[i] = fmt.Sprintf("%s %s", step.Kind, strconv.FormatFloat(step.Duration.Minutes(), 'f', -1, 64))
+	}
+	return strings.Join(parts, ", ")
+}
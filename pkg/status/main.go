@@ -1,6 +1,8 @@
 package status
 
 import (
+	"fmt"
+
 	"github.com/anschnapp/pomodorofactory/pkg/runecolor"
 	"github.com/fatih/color"
 )
@@ -9,15 +11,34 @@ type status struct {
 	width              int
 	height             int
 	asciRepresentation [][]runecolor.ColoredRune
+	position           string // right-aligned on line 1, e.g. "deep 2/3"
+	summary            Summary
+	setSize            int // pomodoros per set, for SummarySets
 }
 
+// Summary is how the achievements row is shortened once it no longer fits.
+type Summary string
+
+const (
+	SummaryRecent Summary = "recent" // the latest ones after "+12 more"
+	SummaryCounts Summary = "counts" // 🍅×7 ☕×3 🐧×2
+	SummarySets   Summary = "sets"   // one group per set; older sets are counted
+)
+
+// Summaries lists the ways to shorten the achievements row.
+var Summaries = []Summary{SummaryRecent, SummaryCounts, SummarySets}
+
+var summaryColor = []color.Attribute{color.FgHiBlack}
+
 // Fixed width so the view region is large enough for any status text
 const statusWidth = 50
 
 func MakeStatus() *status {
 	s := &status{
-		height: 2,
-		width:  statusWidth,
+		height:  2,
+		width:   statusWidth,
+		summary: SummaryRecent,
+		setSize: 4,
 	}
 	s.SetText("Factory ready  press [s] to start", "")
 	return s
@@ -38,25 +59,117 @@ func (s *status) SetAchievements(line1 string, emojis []string) {
 	asci := make([][]runecolor.ColoredRune, 2)
 	asci[0] = runecolor.ConvertSimpleRunes([]rune(line1))
 	if len(emojis) > 0 {
+		asci[1] = s.achievementLine(emojis)
+	} else {
+		asci[1] = nil
+	}
+	s.asciRepresentation = asci
+}
+
+// SetSummary picks how the achievements row is shortened when it is wider
+// than the status; setSize is the number of pomodoros in a set.
+func (s *status) SetSummary(summary Summary, setSize int) {
+	s.summary = summary
+	s.setSize = max(setSize, 1)
+}
+
+func (s *status) achievementLine(emojis []string) []runecolor.ColoredRune {
+	if line := joinEmojis(emojis); len(line) <= statusWidth {
+		return line
+	}
+	switch s.summary {
+	case SummaryCounts:
+		return countEmojis(emojis)
+	case SummarySets:
+		return s.setEmojis(emojis)
+	}
+	return recentEmojis(emojis, nil)
+}
+
+// joinEmojis lays out emojis separated by spaces.
+func joinEmojis(emojis []string) []runecolor.ColoredRune {
+	var line []runecolor.ColoredRune
+	for i, emoji := range emojis {
+		if i > 0 {
+			line = append(line, runecolor.ColoredRune{Symbol: ' '})
+		}
+		emojiRunes := []rune(emoji)
+		for _, r := range emojiRunes {
+			line = append(line, runecolor.ColoredRune{Symbol: r})
+		}
+		for j := len(emojiRunes); j < 2; j++ {
+			line = append(line, runecolor.ColoredRune{Symbol: 0})
+		}
+	}
+	return append(line, runecolor.ColoredRune{Symbol: ' '})
+}
+
+// recentEmojis shows as many of the latest emojis as fit after prefix and
+// a "+12 more" marker for the rest.
+func recentEmojis(emojis []string, prefix []runecolor.ColoredRune) []runecolor.ColoredRune {
+	for n := len(emojis); n > 0; n-- {
+		line := append([]runecolor.ColoredRune{}, prefix...)
+		if n < len(emojis) {
+			line = append(line, runecolor.ConvertRunesToColoredRunes([]rune(fmt.Sprintf("+%d more ", len(emojis)-n)), nil, summaryColor)...)
+		}
+		line = append(line, joinEmojis(emojis[len(emojis)-n:])...)
+		if len(line) <= statusWidth {
+			return line
+		}
+	}
+	return prefix
+}
+
+// countEmojis shows each product once with how often it was built, in the
+// order they were first built.
+func countEmojis(emojis []string) []runecolor.ColoredRune {
+	counts := make(map[string]int)
+	var order []string
+	for _, e := range emojis {
+		if counts[e] == 0 {
+			order = append(order, e)
+		}
+		counts[e]++
+	}
+	var line []runecolor.ColoredRune
+	for _, e := range order {
+		item := joinEmojis([]string{e})
+		item = append(item[:len(item)-1], runecolor.ConvertSimpleRunes([]rune(fmt.Sprintf("×%d  ", counts[e])))...)
+		if len(line)+len(item) > statusWidth {
+			break
+		}
+		line = append(line, item...)
+	}
+	return line
+}
+
+// setEmojis groups the emojis per set, newest sets last; sets that no
+// longer fit are only counted.
+func (s *status) setEmojis(emojis []string) []runecolor.ColoredRune {
+	var sets [][]string
+	for i := 0; i < len(emojis); i += s.setSize {
+		sets = append(sets, emojis[i:min(i+s.setSize, len(emojis))])
+	}
+	separator := runecolor.ConvertRunesToColoredRunes([]rune("│ "), nil, summaryColor)
+	for dropped := 0; dropped < len(sets); dropped++ {
 		var line []runecolor.ColoredRune
-		for i, emoji := range emojis {
+		if dropped > 0 {
+			line = runecolor.ConvertRunesToColoredRunes([]rune(fmt.Sprintf("%d sets ", dropped)), nil, summaryColor)
+			line = append(line, separator...)
+		}
+		for i, set := range sets[dropped:] {
 			if i > 0 {
-				line = append(line, runecolor.ColoredRune{Symbol: ' '})
-			}
-			emojiRunes := []rune(emoji)
-			for _, r := range emojiRunes {
-				line = append(line, runecolor.ColoredRune{Symbol: r})
-			}
-			for j := len(emojiRunes); j < 2; j++ {
-				line = append(line, runecolor.ColoredRune{Symbol: 0})
+				line = append(line, separator...)
 			}
+			line = append(line, joinEmojis(set)...)
+		}
+		if len(line) <= statusWidth {
+			return line
 		}
-		line = append(line, runecolor.ColoredRune{Symbol: ' '})
-		asci[1] = line
-	} else {
-		asci[1] = nil
 	}
-	s.asciRepresentation = asci
+	// Not even the last set fits: count the others and shorten it
+	prefix := runecolor.ConvertRunesToColoredRunes([]rune(fmt.Sprintf("%d sets ", len(sets)-1)), nil, summaryColor)
+	return recentEmojis(sets[len(sets)-1], append(prefix, separator...))
 }
 
 var celebColors = []color.Attribute{
@@ -128,6 +241,12 @@ func (s *status) SetSpeechText(message string, highlightIdx int) {
 	s.asciRepresentation = [][]runecolor.ColoredRune{line1, line2}
 }
 
+// SetPosition sets the schedule position shown at the right end of line 1.
+// It is hidden while line 1 is too long to leave room for it.
+func (s *status) SetPosition(text string) {
+	s.position = text
+}
+
 func (s *status) Width() int {
 	return s.width
 }
@@ -155,4 +274,16 @@ func (c *status) Render(subview [][]runecolor.ColoredRune) {
 			subview[i][j] = c.asciRepresentation[i][j]
 		}
 	}
+
+	pos := []rune(c.position)
+	if len(subview) == 0 || len(pos) == 0 {
+		return
+	}
+	start := len(subview[0]) - len(pos)
+	if len(c.asciRepresentation) > 0 && len(c.asciRepresentation[0])+2 > start {
+		return
+	}
+	for j, r := range pos {
+		subview[0][start+j] = runecolor.ColoredRune{Symbol: r, ColorAttributes: []color.Attribute{color.FgHiBlack}}
+	}
 }
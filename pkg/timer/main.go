@@ -7,6 +7,12 @@ type Timer struct {
 	startTime time.Time
 	running   bool
 	finished  bool
+	open      bool // stopwatch: counts up with no target
+
+	// Progress and elapsed time at the last Extend; the rest of the
+	// progress is spread over the time left from there
+	baseProgress float64
+	baseElapsed  time.Duration
 }
 
 func NewTimer(duration time.Duration) *Timer {
@@ -19,6 +25,7 @@ func (t *Timer) Start() {
 	t.startTime = time.Now().Round(0) // strip monotonic reading so elapsed time uses wall clock (advances during sleep)
 	t.running = true
 	t.finished = false
+	t.baseProgress, t.baseElapsed = 0, 0
 }
 
 // Reset prepares the timer for a new countdown with the given duration.
@@ -26,6 +33,41 @@ func (t *Timer) Reset(duration time.Duration) {
 	t.duration = duration
 	t.running = false
 	t.finished = false
+	t.open = false
+	t.baseProgress, t.baseElapsed = 0, 0
+}
+
+// ResetStopwatch prepares the timer to count up with no target. It only
+// finishes when Finish is called; Progress stays 0, use Elapsed instead.
+func (t *Timer) ResetStopwatch() {
+	t.Reset(0)
+	t.open = true
+}
+
+func (t *Timer) IsStopwatch() bool {
+	return t.open
+}
+
+// Finish ends the timer now as if it had run out.
+func (t *Timer) Finish() {
+	t.running = false
+	t.finished = true
+}
+
+// Extend adds delta (negative to shorten) to a running timer while keeping
+// the time already elapsed. Progress holds its value and speeds up or slows
+// down to fill the time left, so it never runs backwards. Shortening below
+// the elapsed time finishes the timer on the next Progress call.
+func (t *Timer) Extend(delta time.Duration) {
+	if !t.running || t.open {
+		return
+	}
+	t.baseProgress = t.Progress()
+	t.baseElapsed = time.Since(t.startTime)
+	t.duration += delta
+	if t.duration < t.baseElapsed {
+		t.duration = t.baseElapsed
+	}
 }
 
 func (t *Timer) IsRunning() bool {
@@ -41,19 +83,27 @@ func (t *Timer) Elapsed() time.Duration {
 		return 0
 	}
 	elapsed := time.Since(t.startTime)
-	if elapsed > t.duration {
+	if elapsed > t.duration && !t.open {
 		return t.duration
 	}
 	return elapsed
 }
 
+// Duration is the length of the countdown, including any Extend.
+func (t *Timer) Duration() time.Duration {
+	return t.duration
+}
+
 func (t *Timer) Remaining() time.Duration {
+	if t.open {
+		return 0
+	}
 	return t.duration - t.Elapsed()
 }
 
 // Progress returns 0.0–1.0 how far the timer has progressed.
 func (t *Timer) Progress() float64 {
-	if !t.running {
+	if !t.running || t.open {
 		return 0
 	}
 	elapsed := time.Since(t.startTime)
@@ -62,12 +112,13 @@ func (t *Timer) Progress() float64 {
 		t.finished = true
 		return 1.0
 	}
-	return float64(elapsed) / float64(t.duration)
+	rest := float64(elapsed-t.baseElapsed) / float64(t.duration-t.baseElapsed)
+	return min(max(t.baseProgress+(1-t.baseProgress)*rest, 0), 1)
 }
 
 // Percentage returns 0–100 how far the timer has progressed.
 func (t *Timer) Percentage() int {
-	if !t.running {
+	if !t.running || t.open {
 		return 0
 	}
 	elapsed := time.Since(t.startTime)
@@ -26,11 +26,15 @@ var renderObjMargin = struct {
 type viewRegionRenderableBundle struct {
 	renderable render.Renderable
 	viewRegion [][]runecolor.ColoredRune
+	origin     point // upper-left corner of viewRegion in completeView
 }
 
 type View struct {
 	viewRegionRenderableBundle []viewRegionRenderableBundle
 	completeView               [][]runecolor.ColoredRune
+	background                 [][]runecolor.ColoredRune // the empty canvas, to wipe an overlay
+	overlay                    render.Renderable
+	overlayDrawn               bool
 }
 
 func (viewRenderableBundle *viewRegionRenderableBundle) renderViewRegion() {
@@ -75,6 +79,7 @@ func MakeView(topLeft render.Renderable, topRight render.Renderable, middle rend
 	return &View{
 		viewRegionRenderableBundle: renderBundles,
 		completeView:               completeView,
+		background:                 generateCompleteViewWithBorder(height, width),
 	}
 }
 
@@ -89,9 +94,97 @@ func max(values ...int) int {
 }
 
 func (v *View) Render() {
+	// Components only redraw their own regions, so whatever an overlay
+	// covered in the margins has to be wiped first
+	if v.overlay != nil || v.overlayDrawn {
+		for i := range v.completeView {
+			copy(v.completeView[i], v.background[i])
+		}
+	}
 	for _, renderBundle := range v.viewRegionRenderableBundle {
 		renderBundle.renderViewRegion()
 	}
+	v.overlayDrawn = v.overlay != nil
+	if v.overlay != nil {
+		v.renderOverlay()
+	}
+}
+
+// SetOverlay shows r centered in a frame on top of the four regions until
+// ClearOverlay is called. r may change its size between renders.
+func (v *View) SetOverlay(r render.Renderable) {
+	v.overlay = r
+}
+
+func (v *View) ClearOverlay() {
+	v.overlay = nil
+}
+
+// Overlay returns the overlay being shown, or nil.
+func (v *View) Overlay() render.Renderable {
+	return v.overlay
+}
+
+func (v *View) HasOverlay() bool {
+	return v.overlay != nil
+}
+
+// overlayPadding is the space between the overlay frame and its content.
+const overlayPadding = 2
+
+func (v *View) renderOverlay() {
+	height := len(v.completeView)
+	width := len(v.completeView[0])
+	// Content plus padding and frame, clipped to the inside of the border
+	boxHeight := min(v.overlay.Height()+2+2, height-2)
+	boxWidth := min(v.overlay.Width()+2*overlayPadding+2, width-2)
+	top := (height - boxHeight) / 2
+	left := (width - boxWidth) / 2
+
+	frameColor := runecolor.MakeSingleColorAttributes(color.FgHiWhite)
+	for i := top; i < top+boxHeight; i++ {
+		for j := left; j < left+boxWidth; j++ {
+			symbol := ' '
+			switch {
+			case i == top && j == left:
+				symbol = '┌'
+			case i == top && j == left+boxWidth-1:
+				symbol = '┐'
+			case i == top+boxHeight-1 && j == left:
+				symbol = '└'
+			case i == top+boxHeight-1 && j == left+boxWidth-1:
+				symbol = '┘'
+			case i == top || i == top+boxHeight-1:
+				symbol = '─'
+			case j == left || j == left+boxWidth-1:
+				symbol = '│'
+			}
+			v.completeView[i][j] = runecolor.ColoredRune{Symbol: symbol, ColorAttributes: frameColor}
+			if symbol == ' ' {
+				v.completeView[i][j].ColorAttributes = nil
+			}
+		}
+	}
+
+	region := extractViewRegionFromView(v.completeView, boxHeight-4, boxWidth-2-2*overlayPadding, point{
+		lineIndex:   top + 2,
+		columnIndex: left + 1 + overlayPadding,
+	})
+	v.overlay.Render(region)
+}
+
+// RegionAt maps a canvas position (e.g. a mouse click; the canvas is printed
+// from the terminal's home position) to the renderable owning it and the
+// position relative to that renderable's region.
+func (v *View) RegionAt(line, column int) (render.Renderable, int, int, bool) {
+	for _, b := range v.viewRegionRenderableBundle {
+		row := line - b.origin.lineIndex
+		col := column - b.origin.columnIndex
+		if row >= 0 && row < len(b.viewRegion) && col >= 0 && col < len(b.viewRegion[row]) {
+			return b.renderable, row, col, true
+		}
+	}
+	return nil, 0, 0, false
 }
 
 func (v *View) Print() {
@@ -127,6 +220,27 @@ func (v *View) Print() {
 	os.Stdout.WriteString(buf.String())
 }
 
+// BorderColor is a truecolor background for the border.
+func BorderColor(r, g, b int) []color.Attribute {
+	// SGR sequence: background, defined in RGB with the next three attributes
+	return []color.Attribute{48, 2, color.Attribute(r), color.Attribute(g), color.Attribute(b)}
+}
+
+// SetBorderColor repaints the border around the canvas.
+func (v *View) SetBorderColor(attrs []color.Attribute) {
+	for _, canvas := range [][][]runecolor.ColoredRune{v.completeView, v.background} {
+		height := len(canvas)
+		for i := range canvas {
+			width := len(canvas[i])
+			for j := range canvas[i] {
+				if i == 0 || i == height-1 || j == 0 || j == width-1 {
+					canvas[i][j].ColorAttributes = attrs
+				}
+			}
+		}
+	}
+}
+
 func generateCompleteViewWithBorder(height int, width int) [][]runecolor.ColoredRune {
 	view := make([][]runecolor.ColoredRune, height)
 
@@ -134,18 +248,7 @@ func generateCompleteViewWithBorder(height int, width int) [][]runecolor.Colored
 		view[i] = make([]runecolor.ColoredRune, width)
 	}
 
-	borderColor := make([]color.Attribute, 5)
-	// SGR sequence
-	// background
-	borderColor[0] = 48
-	// define in RGB with next three attributes
-	borderColor[1] = 2
-	// R
-	borderColor[2] = 100
-	// G
-	borderColor[3] = 100
-	// B
-	borderColor[4] = 100
+	borderColor := BorderColor(100, 100, 100)
 	for i := range view {
 		for j := range view[i] {
 			var currentRune runecolor.ColoredRune
@@ -183,6 +286,7 @@ func createRenderBundle(renderable render.Renderable, completeView [][]runecolor
 	return viewRegionRenderableBundle{
 		renderable: renderable,
 		viewRegion: viewRegion,
+		origin:     upperLeftStartingPoint,
 	}
 }
 
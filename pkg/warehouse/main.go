@@ -0,0 +1,55 @@
+package warehouse
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"time"
+)
+
+// Entry is one finished product.
+type Entry struct {
+	Product string    `json:"product"`
+	Emoji   string    `json:"emoji"`
+	Built   time.Time `json:"built"`
+}
+
+// Stock is everything the factory ever built, oldest first.
+type Stock struct {
+	Entries []Entry `json:"entries"`
+}
+
+// Load reads a stock file. A missing file yields an empty warehouse.
+func Load(path string) (*Stock, error) {
+	s := &Stock{}
+	data, err := os.ReadFile(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return s, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	if err := json.Unmarshal(data, s); err != nil {
+		return nil, fmt.Errorf("%s: %w", path, err)
+	}
+	return s, nil
+}
+
+// Save writes the stock, creating the directory if needed.
+func (s *Stock) Save(path string) error {
+	data, err := json.MarshalIndent(s, "", "  ")
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		return err
+	}
+	return os.WriteFile(path, append(data, '\n'), 0o644)
+}
+
+// Add stores a finished product.
+func (s *Stock) Add(product, emoji string, built time.Time) {
+	s.Entries = append(s.Entries, Entry{Product: product, Emoji: emoji, Built: built})
+}
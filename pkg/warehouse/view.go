@@ -0,0 +1,241 @@
+package warehouse
+
+import (
+	"fmt"
+	"sort"
+	"time"
+
+	"github.com/anschnapp/pomodorofactory/pkg/input"
+	"github.com/anschnapp/pomodorofactory/pkg/runecolor"
+	"github.com/fatih/color"
+)
+
+// Grouping is how the shelves are split up.
+type Grouping int
+
+const (
+	ByDay Grouping = iota
+	ByWeek
+)
+
+const footer = "↑↓ scroll  g by day/week  other keys close"
+
+const (
+	shelfWidth   = 44 // columns of a shelf
+	visibleLines = 10 // shelf lines shown at once; the rest scrolls
+)
+
+var (
+	titleColor = []color.Attribute{color.Bold, color.FgHiYellow}
+	labelColor = []color.Attribute{color.FgHiCyan}
+	faintColor = []color.Attribute{color.Faint}
+	boardColor = []color.Attribute{color.FgHiBlack}
+)
+
+type view struct {
+	stock    *Stock
+	grouping Grouping
+	scroll   int // first shelf line shown
+
+	header  [][]runecolor.ColoredRune // title and counts per product
+	shelves [][]runecolor.ColoredRune // all shelf lines, newest first
+	width   int
+	height  int
+}
+
+// MakeWarehouseView shows the stock on shelves, newest first. Call Refresh
+// after the stock changed.
+func MakeWarehouseView(stock *Stock) *view {
+	v := &view{stock: stock}
+	v.Refresh()
+	return v
+}
+
+// Refresh lays out the shelves again, keeping the scroll position.
+func (v *view) Refresh() {
+	v.shelves = v.shelfLines()
+	v.scroll = max(0, min(v.scroll, len(v.shelves)-visibleLines))
+	v.header = v.headerLines()
+
+	v.width = shelfWidth
+	v.height = len(v.header) + min(len(v.shelves), visibleLines) + 2
+}
+
+// HandleKey scrolls or regroups the shelves. It returns false for keys
+// it doesn't use, which close the warehouse.
+func (v *view) HandleKey(ev input.KeyEvent) bool {
+	switch {
+	case ev.Key == input.KeyUp || ev.String() == "k" || ev.Key == input.KeyMouse && ev.Mouse.Button == input.MouseWheelUp:
+		v.scrollBy(-1)
+	case ev.Key == input.KeyDown || ev.String() == "j" || ev.Key == input.KeyMouse && ev.Mouse.Button == input.MouseWheelDown:
+		v.scrollBy(1)
+	case ev.Key == input.KeyPageUp:
+		v.scrollBy(-visibleLines)
+	case ev.Key == input.KeyPageDown || ev.String() == "space":
+		v.scrollBy(visibleLines)
+	case ev.Key == input.KeyHome:
+		v.scrollBy(-len(v.shelves))
+	case ev.Key == input.KeyEnd:
+		v.scrollBy(len(v.shelves))
+	case ev.String() == "g":
+		v.grouping = 1 - v.grouping
+		v.scroll = 0
+		v.Refresh()
+	case ev.Key == input.KeyMouse && !ev.IsClick():
+		// ignore motion and releases
+	default:
+		return false
+	}
+	return true
+}
+
+func (v *view) scrollBy(n int) {
+	v.scroll = max(0, min(v.scroll+n, len(v.shelves)-visibleLines))
+	v.header = v.headerLines()
+}
+
+func (v *view) headerLines() [][]runecolor.ColoredRune {
+	title := colored(fmt.Sprintf("Warehouse  %d built", len(v.stock.Entries)), titleColor)
+	by := "by day"
+	if v.grouping == ByWeek {
+		by = "by week"
+	}
+	if len(v.shelves) > visibleLines {
+		by = fmt.Sprintf("%s  %d–%d/%d", by, v.scroll+1, v.scroll+visibleLines, len(v.shelves))
+	}
+	lines := [][]runecolor.ColoredRune{rightAlign(title, colored(by, faintColor))}
+
+	// Counts per product, most built first
+	counts := make(map[string]int)
+	emojis := make(map[string]string)
+	var names []string
+	for _, e := range v.stock.Entries {
+		if counts[e.Product] == 0 {
+			names = append(names, e.Product)
+		}
+		counts[e.Product]++
+		emojis[e.Product] = e.Emoji
+	}
+	sort.SliceStable(names, func(i, j int) bool { return counts[names[i]] > counts[names[j]] })
+	items := make([][]runecolor.ColoredRune, len(names))
+	for i, name := range names {
+		items[i] = append(emojiCells(emojis[name]), colored(fmt.Sprintf("×%d", counts[name]), nil)...)
+	}
+	lines = append(lines, wrap(items, 2)...)
+	return append(lines, nil)
+}
+
+func (v *view) shelfLines() [][]runecolor.ColoredRune {
+	if len(v.stock.Entries) == 0 {
+		return [][]runecolor.ColoredRune{colored("Nothing built yet. The shelves are waiting.", faintColor)}
+	}
+	var lines [][]runecolor.ColoredRune
+	var label string
+	var items [][]runecolor.ColoredRune
+	flush := func() {
+		if len(items) == 0 {
+			return
+		}
+		lines = append(lines, rightAlign(colored(label, labelColor), colored(fmt.Sprintf("%d", len(items)), faintColor)))
+		lines = append(lines, wrap(items, 1)...)
+		board := make([]runecolor.ColoredRune, shelfWidth)
+		for i := range board {
+			board[i] = runecolor.ColoredRune{Symbol: '▔', ColorAttributes: boardColor}
+		}
+		lines = append(lines, board)
+		items = nil
+	}
+	for i := len(v.stock.Entries) - 1; i >= 0; i-- {
+		e := v.stock.Entries[i]
+		if l := v.shelfLabel(e.Built); l != label {
+			flush()
+			label = l
+		}
+		items = append(items, emojiCells(e.Emoji))
+	}
+	flush()
+	return lines
+}
+
+// shelfLabel names the day or week something was built in.
+func (v *view) shelfLabel(t time.Time) string {
+	t = t.Local()
+	if v.grouping == ByDay {
+		return t.Format("Mon 2 Jan 2006")
+	}
+	year, week := t.ISOWeek()
+	monday := t.AddDate(0, 0, -(int(t.Weekday())+6)%7)
+	sunday := monday.AddDate(0, 0, 6)
+	return fmt.Sprintf("Week %d, %d  (%s – %s)", week, year, monday.Format("2 Jan"), sunday.Format("2 Jan"))
+}
+
+func (v *view) Width() int {
+	return v.width
+}
+
+func (v *view) Height() int {
+	return v.height
+}
+
+func (v *view) Render(subview [][]runecolor.ColoredRune) {
+	lines := append([][]runecolor.ColoredRune{}, v.header...)
+	lines = append(lines, v.shelves[v.scroll:min(v.scroll+visibleLines, len(v.shelves))]...)
+	lines = append(lines, nil, colored(footer, faintColor))
+	for i := range subview {
+		for j := range subview[i] {
+			subview[i][j] = runecolor.ColoredRune{Symbol: ' '}
+		}
+		if i < len(lines) {
+			copy(subview[i], lines[i])
+		}
+	}
+}
+
+// wrap joins items with gap spaces into lines no wider than a shelf.
+func wrap(items [][]runecolor.ColoredRune, gap int) [][]runecolor.ColoredRune {
+	var lines [][]runecolor.ColoredRune
+	var line []runecolor.ColoredRune
+	for _, item := range items {
+		if len(line) > 0 && len(line)+gap+len(item) > shelfWidth {
+			lines = append(lines, line)
+			line = nil
+		}
+		if len(line) > 0 {
+			for i := 0; i < gap; i++ {
+				line = append(line, runecolor.ColoredRune{Symbol: ' '})
+			}
+		}
+		line = append(line, item...)
+	}
+	if len(line) > 0 {
+		lines = append(lines, line)
+	}
+	return lines
+}
+
+// rightAlign puts right at the end of a shelf-wide line starting with left.
+func rightAlign(left, right []runecolor.ColoredRune) []runecolor.ColoredRune {
+	line := append([]runecolor.ColoredRune{}, left...)
+	for len(line)+len(right) < shelfWidth {
+		line = append(line, runecolor.ColoredRune{Symbol: ' '})
+	}
+	return append(line, right...)
+}
+
+// emojiCells takes two canvas cells per emoji; a zero-width sentinel
+// reserves the trailing cell of a single-rune emoji, as in the status line.
+func emojiCells(emoji string) []runecolor.ColoredRune {
+	var cells []runecolor.ColoredRune
+	runes := []rune(emoji)
+	for _, r := range runes {
+		cells = append(cells, runecolor.ColoredRune{Symbol: r})
+	}
+	for j := len(runes); j < 2; j++ {
+		cells = append(cells, runecolor.ColoredRune{Symbol: 0})
+	}
+	return cells
+}
+
+func colored(text string, attrs []color.Attribute) []runecolor.ColoredRune {
+	return runecolor.ConvertRunesToColoredRunes([]rune(text), nil, attrs)
+}
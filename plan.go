@@ -0,0 +1,128 @@
+package main
+
+import (
+	"fmt"
+	"sort"
+	"strconv"
+
+	"github.com/anschnapp/pomodorofactory/pkg/helpoverlay"
+	"github.com/anschnapp/pomodorofactory/pkg/plan"
+	"github.com/anschnapp/pomodorofactory/pkg/product"
+)
+
+// parsePlanEdit turns the arguments of ":plan <edit>" into a change to the plan.
+func parsePlanEdit(args []string, products []*product.Product) (func(*plan.Plan) error, error) {
+	usage := fmt.Errorf("usage: :plan [add <product> [count] | rm <n> | mv <from> <to> | clear]")
+	if len(args) == 0 {
+		return nil, usage
+	}
+	numbers := func(args []string) ([]int, error) {
+		ns := make([]int, len(args))
+		for i, a := range args {
+			n, err := strconv.Atoi(a)
+			if err != nil {
+				return nil, fmt.Errorf("expected a position, got %q", a)
+			}
+			ns[i] = n
+		}
+		return ns, nil
+	}
+
+	switch args[0] {
+	case "add":
+		if len(args) < 2 || len(args) > 3 {
+			return nil, usage
+		}
+		idx, err := lookupProduct(products, args[1])
+		if err != nil {
+			return nil, err
+		}
+		count := 1
+		if len(args) == 3 {
+			if count, err = strconv.Atoi(args[2]); err != nil || count < 1 {
+				return nil, fmt.Errorf("expected a count, got %q", args[2])
+			}
+		}
+		name := products[idx].Name
+		return func(p *plan.Plan) error { return p.Add(name, count) }, nil
+	case "rm":
+		ns, err := numbers(args[1:])
+		if err != nil || len(ns) != 1 {
+			return nil, usage
+		}
+		return func(p *plan.Plan) error { return p.Remove(ns[0]) }, nil
+	case "mv":
+		ns, err := numbers(args[1:])
+		if err != nil || len(ns) != 2 {
+			return nil, usage
+		}
+		return func(p *plan.Plan) error { return p.Move(ns[0], ns[1]) }, nil
+	case "clear":
+		return func(p *plan.Plan) error { p.Clear(); return nil }, nil
+	}
+	return nil, usage
+}
+
+// planContent lists today's queue and what was built besides it.
+func planContent(p *plan.Plan) (string, []helpoverlay.Section) {
+	var queue []helpoverlay.Row
+	for i, item := range p.Items {
+		mark := "[ ]"
+		if item.Done {
+			mark = "[x]"
+		}
+		queue = append(queue, helpoverlay.Row{Key: fmt.Sprintf("%d. %s", i+1, mark), Description: item.Product})
+	}
+	if len(queue) == 0 {
+		queue = append(queue, helpoverlay.Row{Key: "nothing planned yet"})
+	}
+	done, total := p.Progress()
+	sections := []helpoverlay.Section{
+		{Title: fmt.Sprintf("Queue  %d/%d built", done, total), Rows: queue},
+	}
+	if extra := countProducts(p.Extra); len(extra) > 0 {
+		sections = append(sections, helpoverlay.Section{Title: "Also built", Rows: extra})
+	}
+	sections = append(sections, helpoverlay.Section{Title: "Edit", Rows: []helpoverlay.Row{
+		{Key: ":plan add <product> [count]"},
+		{Key: ":plan rm <n>"},
+		{Key: ":plan mv <from> <to>"},
+		{Key: ":plan clear"},
+	}})
+	return "Build plan for " + p.Date, sections
+}
+
+// daySummary compares what was planned for a finished day with what got built.
+func daySummary(p *plan.Plan) (string, []helpoverlay.Section) {
+	done, total := p.Progress()
+	var missed []string
+	for _, item := range p.Items {
+		if !item.Done {
+			missed = append(missed, item.Product)
+		}
+	}
+	rows := []helpoverlay.Row{
+		{Key: "planned", Description: strconv.Itoa(total)},
+		{Key: "built as planned", Description: strconv.Itoa(done)},
+		{Key: "built unplanned", Description: strconv.Itoa(len(p.Extra))},
+	}
+	sections := []helpoverlay.Section{{Title: "Planned vs achieved", Rows: rows}}
+	if len(missed) > 0 {
+		sections = append(sections, helpoverlay.Section{Title: "Not built", Rows: countProducts(missed)})
+	}
+	return "Day summary for " + p.Date, sections
+}
+
+// countProducts turns a list of product names into "Name  ×n" rows.
+func countProducts(names []string) []helpoverlay.Row {
+	counts := make(map[string]int)
+	for _, name := range names {
+		counts[name]++
+	}
+	var rows []helpoverlay.Row
+	for name, n := range counts {
+		rows = append(rows, helpoverlay.Row{Key: name, Description: fmt.Sprintf("×%d", n)})
+	}
+	sort.Slice(rows, func(i, j int) bool { return rows[i].Key < rows[j].Key })
+	return rows
+}
@@ -0,0 +1,289 @@
+package main
+
+import (
+	"errors"
+	"flag"
+	"fmt"
+	"image"
+	_ "image/jpeg"
+	_ "image/png"
+	"math/rand"
+	"os"
+	"path/filepath"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+
+	"github.com/anschnapp/pomodorofactory/pkg/artlint"
+	"github.com/anschnapp/pomodorofactory/pkg/configdir"
+	"github.com/anschnapp/pomodorofactory/pkg/factoryscene"
+	"github.com/anschnapp/pomodorofactory/pkg/imageart"
+	"github.com/anschnapp/pomodorofactory/pkg/product"
+	"github.com/anschnapp/pomodorofactory/pkg/runecolor"
+)
+
+const productUsage = `usage:
+  pomodorofactory product import <picture.png|.jpg> [--width 23] [--height 10] [--name Name] [--emoji 🎁] [--keep-background] [--force]
+  pomodorofactory product lint [product]
+  pomodorofactory product preview <product> [--progress 0.5] [--section 1] [--style crane] [--seed 1]`
+
+// runProduct implements the `product` subcommand for adding products
+// without writing Go.
+func runProduct(args []string) error {
+	if len(args) == 0 {
+		return errors.New(productUsage)
+	}
+	switch args[0] {
+	case "import":
+		return importProduct(args[1:])
+	case "lint":
+		return lintProducts(args[1:])
+	case "preview":
+		return previewProduct(args[1:])
+	default:
+		return fmt.Errorf("unknown product command %q\n%s", args[0], productUsage)
+	}
+}
+
+// importProduct converts a picture into a product in the products folder
+// and prints a preview.
+func importProduct(args []string) error {
+	fs := flag.NewFlagSet("product import", flag.ContinueOnError)
+	width := fs.Int("width", artlint.MaxWidth, "art width in cells")
+	height := fs.Int("height", artlint.MaxHeight, "art height in rows")
+	name := fs.String("name", "", "product name (default: from the file name)")
+	emoji := fs.String("emoji", "📦", "emoji shown for the finished product")
+	keepBackground := fs.Bool("keep-background", false, "draw cells matching the picture's corner color too")
+	force := fs.Bool("force", false, "replace a product file of the same name")
+	path, err := parseInterspersed(fs, args)
+	if err != nil {
+		return err
+	}
+	if path == "" {
+		return errors.New(productUsage)
+	}
+	// Bigger art would grow the canvas for every product
+	if *width < 1 || *width > artlint.MaxWidth || *height < 1 || *height > artlint.MaxHeight {
+		return fmt.Errorf("the art can be 1 to %d cells wide and 1 to %d rows high", artlint.MaxWidth, artlint.MaxHeight)
+	}
+	if *name == "" {
+		*name = nameFromFile(path)
+	}
+	dir, err := configdir.Path("products")
+	if err != nil {
+		return err
+	}
+	builtIn := len(product.All)
+	if err := loadCustomProducts(); err != nil {
+		return err
+	}
+	for i, p := range product.All {
+		if strings.EqualFold(p.Name, *name) && (i < builtIn || !*force) {
+			return fmt.Errorf("there is already a product called %q; pick another with --name", p.Name)
+		}
+	}
+	if _, err := os.Stat(filepath.Join(dir, product.Slug(*name)+".json")); err == nil && !*force {
+		return fmt.Errorf("%s already exists; pick another --name or replace it with --force", product.Slug(*name)+".json")
+	}
+
+	f, err := os.Open(path)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+	img, _, err := image.Decode(f)
+	if err != nil {
+		return fmt.Errorf("%s: %w", path, err)
+	}
+
+	art, colors := imageart.Convert(img, imageart.Options{Width: *width, Height: *height, KeepBackground: *keepBackground})
+	if len(art) == 0 {
+		return errors.New("the picture came out empty; try --keep-background")
+	}
+	custom := &product.Custom{Name: *name, Emoji: *emoji, Art: art, Colors: colors}
+	p, err := custom.Product()
+	if err != nil {
+		return err
+	}
+	saved, err := custom.Save(dir)
+	if err != nil {
+		return err
+	}
+	printArt(p)
+	fmt.Printf("saved %s %s to %s\n", p.Emoji, p.Name, saved)
+	return nil
+}
+
+// lintProducts checks the art of every product, or of one, and fails if
+// any of it is broken.
+func lintProducts(args []string) error {
+	if len(args) > 1 {
+		return errors.New(productUsage)
+	}
+	if err := loadCustomProducts(); err != nil {
+		return err
+	}
+	products := product.All
+	if len(args) == 1 {
+		idx, err := lookupProduct(products, args[0])
+		if err != nil {
+			return err
+		}
+		products = products[idx : idx+1]
+	}
+	errs := 0
+	warnings := 0
+	for _, p := range products {
+		for _, problem := range artlint.Check(p) {
+			fmt.Println(problem)
+			if problem.Severity == artlint.Error {
+				errs++
+			} else {
+				warnings++
+			}
+		}
+	}
+	fmt.Printf("%d products: %d errors, %d warnings\n", len(products), errs, warnings)
+	if errs > 0 {
+		return errors.New("art has errors")
+	}
+	return nil
+}
+
+// previewProduct prints one frame of a product being built, on the same
+// canvas the factory uses.
+func previewProduct(args []string) error {
+	fs := flag.NewFlagSet("product preview", flag.ContinueOnError)
+	progress := fs.Float64("progress", 0.5, "how far along the build is, 0 to 1")
+	section := fs.Int("section", 1, "section of a mega-build to show")
+	styleName := fs.String("style", "", "build style (default: the product's own)")
+	seed := fs.Int64("seed", 1, "seed for the sparks")
+	name, err := parseInterspersed(fs, args)
+	if err != nil {
+		return err
+	}
+	if name == "" {
+		return errors.New(productUsage)
+	}
+	if err := loadCustomProducts(); err != nil {
+		return err
+	}
+	idx, err := lookupProduct(product.All, name)
+	if err != nil {
+		return err
+	}
+	p := product.All[idx]
+	if *styleName == "" {
+		*styleName = p.Style
+	}
+	style, ok := factoryscene.StyleByName(*styleName)
+	if !ok && *styleName != "" {
+		return fmt.Errorf("unknown build style %q (expected one of: %s)", *styleName, strings.Join(factoryscene.StyleNames(), ", "))
+	}
+
+	factory := factoryscene.MakeFactoryScene(product.All, rand.New(rand.NewSource(*seed)))
+	factory.SetStyle(style)
+	factory.LoadArt(p.Art)
+	factory.SetAnimation(p.Frames)
+	if p.IsMega() {
+		factory.SetSection(*section-1, p.Sessions)
+	}
+	factory.SetProgress(*progress)
+
+	canvas := make([][]runecolor.ColoredRune, factory.Height())
+	for i := range canvas {
+		canvas[i] = make([]runecolor.ColoredRune, factory.Width())
+	}
+	factory.Render(canvas)
+	for _, line := range canvas {
+		fmt.Println(strings.TrimRight(runecolor.ANSI(line), " "))
+	}
+	return nil
+}
+
+// customToEdit loads the product file called name for the art editor, or
+// starts a new product. Built-in products can't be edited.
+func customToEdit(name string, products []*product.Product) (*product.Custom, error) {
+	dir, err := configdir.Path("products")
+	if err != nil {
+		return nil, err
+	}
+	c, err := product.ReadCustom(filepath.Join(dir, product.Slug(name)+".json"))
+	if err == nil {
+		return c, nil
+	}
+	if !errors.Is(err, os.ErrNotExist) {
+		return nil, err
+	}
+	for _, p := range products {
+		if strings.EqualFold(p.Name, name) {
+			return nil, fmt.Errorf("%s is built in; pick another name", p.Name)
+		}
+	}
+	return &product.Custom{Name: name, Emoji: "📦"}, nil
+}
+
+// loadCustomProducts adds the products in the products folder to
+// product.All, after the built-in ones.
+func loadCustomProducts() error {
+	dir, err := configdir.Path("products")
+	if err != nil {
+		return nil
+	}
+	custom, err := product.LoadCustom(dir)
+	if err != nil {
+		return err
+	}
+	product.All = append(product.All, custom...)
+	return nil
+}
+
+// parseInterspersed parses flags given before or after the one positional
+// argument, which it returns.
+func parseInterspersed(fs *flag.FlagSet, args []string) (string, error) {
+	positional, err := parseFlagsAnywhere(fs, args)
+	if err != nil {
+		return "", err
+	}
+	if len(positional) > 1 {
+		return "", fmt.Errorf("unexpected argument %q", positional[1])
+	}
+	if len(positional) == 0 {
+		return "", nil
+	}
+	return positional[0], nil
+}
+
+// parseFlagsAnywhere parses flags given between the positional arguments,
+// which it returns.
+func parseFlagsAnywhere(fs *flag.FlagSet, args []string) ([]string, error) {
+	var positional []string
+	for {
+		if err := fs.Parse(args); err != nil {
+			return nil, err
+		}
+		if fs.NArg() == 0 {
+			return positional, nil
+		}
+		positional = append(positional, fs.Arg(0))
+		args = fs.Args()[1:]
+	}
+}
+
+// nameFromFile turns "my_rubber-duck.png" into "My Rubber Duck" and
+// "éclair.png" into "Éclair".
+func nameFromFile(path string) string {
+	base := strings.TrimSuffix(filepath.Base(path), filepath.Ext(path))
+	words := strings.FieldsFunc(base, func(r rune) bool { return r == '_' || r == '-' || r == ' ' })
+	for i, w := range words {
+		first, size := utf8.DecodeRuneInString(w)
+		words[i] = string(unicode.ToUpper(first)) + w[size:]
+	}
+	return strings.Join(words, " ")
+}
+
+func printArt(p *product.Product) {
+	for _, line := range p.Art {
+		fmt.Println(runecolor.ANSI(line))
+	}
+}
@@ -0,0 +1,65 @@
+package main
+
+import (
+	"fmt"
+	"slices"
+
+	"github.com/anschnapp/pomodorofactory/pkg/factoryscene"
+	"github.com/anschnapp/pomodorofactory/pkg/helpoverlay"
+	"github.com/anschnapp/pomodorofactory/pkg/plan"
+	"github.com/anschnapp/pomodorofactory/pkg/progression"
+	"github.com/anschnapp/pomodorofactory/pkg/view"
+	"github.com/anschnapp/pomodorofactory/pkg/warehouse"
+)
+
+// steelBorder is the border color of the steel blue walls upgrade.
+var steelBorder = view.BorderColor(50, 80, 130)
+
+// seedCounters fills fresh counters from the warehouse, so products built
+// before progression existed still count. Focus time wasn't recorded then.
+func seedCounters(c *progression.Counters, stock *warehouse.Stock) {
+	for _, e := range stock.Entries {
+		c.Build(e.Product)
+		c.Focus(0, e.Built.Local().Format(plan.DateLayout))
+	}
+}
+
+func factoryUpgrades(c *progression.Counters) factoryscene.Upgrades {
+	return factoryscene.Upgrades{
+		BrightSparks: c.Has(progression.BrightSparks),
+		SecondArm:    c.Has(progression.SecondArm),
+		GoldenPillar: c.Has(progression.GoldenPillar),
+	}
+}
+
+// newlyReached returns the milestones in after that aren't in before.
+func newlyReached(before, after []progression.Milestone) []progression.Milestone {
+	var reached []progression.Milestone
+	for _, m := range after {
+		if !slices.ContainsFunc(before, func(b progression.Milestone) bool { return b.Name == m.Name }) {
+			reached = append(reached, m)
+		}
+	}
+	return reached
+}
+
+// lockedText tells how far a locked product is from being unlocked.
+func lockedText(name string, m progression.Milestone, c *progression.Counters) string {
+	return fmt.Sprintf("%s unlocks at %s (%d/%d)", name, m.Name, m.Progress(c), m.Goal)
+}
+
+// milestoneContent announces reached milestones and what they unlock.
+func milestoneContent(reached []progression.Milestone) (string, []helpoverlay.Section) {
+	var sections []helpoverlay.Section
+	for _, m := range reached {
+		var rows []helpoverlay.Row
+		for _, p := range m.Products {
+			rows = append(rows, helpoverlay.Row{Key: p, Description: "unlocked"})
+		}
+		for _, u := range m.Upgrades {
+			rows = append(rows, helpoverlay.Row{Key: string(u), Description: "upgrade"})
+		}
+		sections = append(sections, helpoverlay.Section{Title: m.Name, Rows: rows})
+	}
+	return "Milestone reached!", sections
+}